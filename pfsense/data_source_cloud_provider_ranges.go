@@ -0,0 +1,119 @@
+package pfsense
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// defaultRangePattern matches anything that looks like an IPv4 or IPv6 CIDR, each match is then parsed to validate it
+// as it also matches text such as beef.cafe/999
+const defaultRangePattern = `[0-9a-fA-F]*[:.][0-9a-fA-F:.]+/[0-9]{1,3}`
+
+// extractCidrs finds the CIDRs in the body with the pattern, the first capture group is used when there is one. Matches
+// that aren't a CIDR are skipped.
+func extractCidrs(body string, pattern *regexp.Regexp) []string {
+	var prefixes []netip.Prefix
+
+	for _, match := range pattern.FindAllStringSubmatch(body, -1) {
+		value := match[0]
+
+		if len(match) > 1 {
+			value = match[1]
+		}
+
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		}
+	}
+
+	return sortedCidrs(prefixes)
+}
+
+func dataSourceCloudProviderRanges() *schema.Resource {
+	return &schema.Resource{
+		Description: "Fetches a list of networks, e.g. a cloud provider's published IP ranges, and extracts the CIDRs from it with a regular expression. The sorted, de-duplicated `cidrs` can be used as the targets of a `pfsense_firewall_alias` to keep it in sync with the feed without a URL table alias.",
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			url := d.Get("url").(string)
+			pattern := regexp.MustCompile(d.Get("pattern").(string))
+
+			ctx, cancel := context.WithTimeout(ctx, time.Duration(d.Get("timeout").(int))*time.Second)
+			defer cancel()
+
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+
+			if err != nil {
+				return diag.Errorf("Unable to fetch %s: %v", url, err)
+			}
+
+			defer response.Body.Close()
+
+			if response.StatusCode != http.StatusOK {
+				return diag.Errorf("Unable to fetch %s: %s", url, response.Status)
+			}
+
+			body, err := io.ReadAll(response.Body)
+
+			if err != nil {
+				return diag.Errorf("Unable to read %s: %v", url, err)
+			}
+
+			cidrs := extractCidrs(string(body), pattern)
+
+			if len(cidrs) == 0 {
+				return diag.Errorf("No CIDRs found in %s", url)
+			}
+
+			if err := d.Set("cidrs", cidrs); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(url)
+
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "URL of the list of networks, in any format e.g. JSON or plain text.",
+			},
+			"pattern": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultRangePattern,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression that matches each CIDR, if it has a capture group only the first group is used, e.g. `\"ip_prefix\":\\s*\"([^\"]+)\"`. Matches that aren't a valid CIDR are skipped. Defaults to matching any IPv4 or IPv6 CIDR.",
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Request timeout duration in seconds.",
+			},
+			"cidrs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The CIDRs found, masked to their network address and sorted.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
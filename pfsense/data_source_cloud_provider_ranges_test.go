@@ -0,0 +1,92 @@
+package pfsense
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_dataSourceCloudProviderRanges(t *testing.T) {
+	feeds := map[string]string{
+		"/ranges.json": `{"prefixes": [
+			{"ip_prefix": "3.5.140.0/22", "service": "S3"},
+			{"ip_prefix": "13.34.37.64/27", "service": "EC2"},
+			{"ip_prefix": "3.5.140.0/22", "service": "EC2"}
+		], "ipv6_prefixes": [{"ipv6_prefix": "2600:1f00::/24"}]}`,
+		"/ranges.txt":  "# Published ranges\n10.0.0.1/8\n192.168.1.0/24\n",
+		"/invalid.txt": "10.0.0.0/33\n",
+		"/mixed.txt":   "beef.cafe/999\n10.0.0.0/33\n192.168.1.0/24\n",
+		"/empty.txt":   "# Nothing here\n",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if feed, ok := feeds[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(feed))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := map[string]struct {
+		path     string
+		pattern  string
+		expected []interface{}
+	}{
+		"json":         {"/ranges.json", "", []interface{}{"3.5.140.0/22", "13.34.37.64/27", "2600:1f00::/24"}},
+		"json ipv4":    {"/ranges.json", `"ip_prefix":\s*"([^"]+)"`, []interface{}{"3.5.140.0/22", "13.34.37.64/27"}},
+		"text":         {"/ranges.txt", "", []interface{}{"10.0.0.0/8", "192.168.1.0/24"}},
+		"invalid cidr": {"/invalid.txt", "", nil},
+		"mixed":        {"/mixed.txt", "", []interface{}{"192.168.1.0/24"}},
+		"no cidrs":     {"/empty.txt", "", nil},
+		"not found":    {"/missing.txt", "", nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := dataSourceCloudProviderRanges()
+			config := map[string]interface{}{"url": server.URL + test.path}
+
+			if test.pattern != "" {
+				config["pattern"] = test.pattern
+			}
+
+			d := schema.TestResourceDataRaw(t, r.Schema, config)
+			diags := r.ReadContext(context.Background(), d, nil)
+
+			if test.expected == nil {
+				if !diags.HasError() {
+					t.Errorf("Expected an error but got %v", d.Get("cidrs"))
+				}
+
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("Unexpected error %v", diags)
+			}
+
+			if cidrs := d.Get("cidrs"); !reflect.DeepEqual(cidrs, test.expected) {
+				t.Errorf("Expected %v but got %v", test.expected, cidrs)
+			}
+		})
+	}
+}
+
+func Test_dataSourceCloudProviderRangesTimeout(t *testing.T) {
+	validate := dataSourceCloudProviderRanges().Schema["timeout"].ValidateFunc
+
+	for _, timeout := range []int{0, -1} {
+		if _, errs := validate(timeout, "timeout"); len(errs) == 0 {
+			t.Errorf("Expected timeout %d to be invalid", timeout)
+		}
+	}
+
+	if _, errs := validate(1, "timeout"); len(errs) != 0 {
+		t.Errorf("Expected timeout 1 to be valid but got %v", errs)
+	}
+}
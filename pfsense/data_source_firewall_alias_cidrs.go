@@ -0,0 +1,155 @@
+package pfsense
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+// lastAddress returns the highest address within the prefix
+func lastAddress(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Masked().Addr().AsSlice()
+
+	for i := prefix.Bits(); i < len(bytes)*8; i++ {
+		bytes[i/8] |= 1 << (7 - i%8)
+	}
+
+	last, _ := netip.AddrFromSlice(bytes)
+
+	return last
+}
+
+// rangeToPrefixes covers the range from start to end inclusive with as few prefixes as possible
+func rangeToPrefixes(start, end netip.Addr) []netip.Prefix {
+	var prefixes []netip.Prefix
+
+	for start.IsValid() && start.Compare(end) <= 0 {
+		for bits := 0; bits <= start.BitLen(); bits++ {
+			prefix := netip.PrefixFrom(start, bits)
+
+			if prefix.Masked().Addr() == start && lastAddress(prefix).Compare(end) <= 0 {
+				prefixes = append(prefixes, prefix)
+				start = lastAddress(prefix).Next()
+				break
+			}
+		}
+	}
+
+	return prefixes
+}
+
+// sortedCidrs sorts the prefixes by address and formats them without duplicates
+func sortedCidrs(prefixes []netip.Prefix) []string {
+	slices.SortFunc(prefixes, func(a, b netip.Prefix) int {
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c
+		}
+
+		return a.Bits() - b.Bits()
+	})
+
+	cidrs := []string{}
+
+	for _, prefix := range slices.Compact(prefixes) {
+		cidrs = append(cidrs, prefix.String())
+	}
+
+	return cidrs
+}
+
+// aliasPrefixes resolves the alias entries to prefixes, following nested aliases. Hostnames can't be resolved
+// without DNS so they're an error.
+func aliasPrefixes(aliases []*pfsenseapi.FirewallAlias, name string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	w := newAliasWalk(aliases)
+
+	if _, ok := w.aliases[name]; !ok {
+		return nil, fmt.Errorf("Unable to find alias %s", name)
+	}
+
+	w.alias = func(alias *pfsenseapi.FirewallAlias) error {
+		if !slices.Contains([]string{"host", "network"}, alias.Type) {
+			return fmt.Errorf("Alias %s is a %s alias, only host and network aliases contain addresses", alias.Name, alias.Type)
+		}
+
+		return nil
+	}
+
+	w.entry = func(parent string, entry string) error {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		} else if first, last, found := strings.Cut(entry, "-"); found {
+			start, startErr := netip.ParseAddr(first)
+			end, endErr := netip.ParseAddr(last)
+
+			if startErr != nil || endErr != nil || start.Is4() != end.Is4() || start.Compare(end) > 0 {
+				return fmt.Errorf("Alias %s has an invalid range %s", parent, entry)
+			}
+
+			prefixes = append(prefixes, rangeToPrefixes(start, end)...)
+		} else {
+			return fmt.Errorf("Alias %s contains %s which can't be converted to a CIDR, hostnames aren't resolved", parent, entry)
+		}
+
+		return nil
+	}
+
+	if err := w.walk(nil, []string{name}); err != nil {
+		return nil, err
+	}
+
+	return prefixes, nil
+}
+
+func dataSourceFirewallAliasCidrs() *schema.Resource {
+	return &schema.Resource{
+		Description: "Exports the entries of a host or network alias as a sorted list of CIDRs, addresses become single address CIDRs, ranges the fewest CIDRs that cover them and nested aliases are expanded. Fails if the alias contains a hostname.",
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+
+			aliases, err := client.aliases.ListAliases(ctx)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			name := d.Get("name").(string)
+			prefixes, err := aliasPrefixes(aliases, name)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			if err := d.Set("cidrs", sortedCidrs(prefixes)); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(name)
+
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of an existing host or network alias.",
+			},
+			"cidrs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The alias entries as CIDRs, sorted by address.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
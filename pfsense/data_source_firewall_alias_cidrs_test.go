@@ -0,0 +1,88 @@
+package pfsense
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_rangeToPrefixes(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+	}{
+		{"10.0.0.1", "10.0.0.1", []string{"10.0.0.1/32"}},
+		{"10.0.0.0", "10.0.0.255", []string{"10.0.0.0/24"}},
+		{"10.0.0.1", "10.0.0.10", []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/30", "10.0.0.8/31", "10.0.0.10/32"}},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}},
+		{"2001:db8::", "2001:db8::3", []string{"2001:db8::/126"}},
+	}
+
+	for _, test := range tests {
+		actual := []string{}
+
+		for _, prefix := range rangeToPrefixes(netip.MustParseAddr(test.start), netip.MustParseAddr(test.end)) {
+			actual = append(actual, prefix.String())
+		}
+
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Expected %s-%s to be %v but got %v", test.start, test.end, test.expected, actual)
+		}
+	}
+}
+
+func Test_dataSourceFirewallAliasCidrs(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/alias": `[
+			{"name": "web_servers", "type": "host", "address": "10.0.0.20 10.0.0.10 2001:db8::1"},
+			{"name": "web_networks", "type": "network", "address": "10.1.0.7/24 web_servers 10.0.0.10/32"},
+			{"name": "dhcp_pool", "type": "host", "address": "192.168.1.100-192.168.1.103"},
+			{"name": "web_hosts", "type": "host", "address": "www.example.com"},
+			{"name": "web_ports", "type": "port", "address": "80 443"},
+			{"name": "loop_a", "type": "network", "address": "loop_b"},
+			{"name": "loop_b", "type": "network", "address": "loop_a"}
+		]`,
+	})
+
+	tests := map[string]struct {
+		name     string
+		expected []interface{}
+	}{
+		"hosts":    {"web_servers", []interface{}{"10.0.0.10/32", "10.0.0.20/32", "2001:db8::1/128"}},
+		"nested":   {"web_networks", []interface{}{"10.0.0.10/32", "10.0.0.20/32", "10.1.0.0/24", "2001:db8::1/128"}},
+		"range":    {"dhcp_pool", []interface{}{"192.168.1.100/30"}},
+		"hostname": {"web_hosts", nil},
+		"port":     {"web_ports", nil},
+		"loop":     {"loop_a", nil},
+		"missing":  {"missing", nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := dataSourceFirewallAliasCidrs()
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": test.name})
+
+			diags := r.ReadContext(context.Background(), d, client)
+
+			if test.expected == nil {
+				if !diags.HasError() {
+					t.Errorf("Expected an error but got %v", d.Get("cidrs"))
+				}
+
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("Unexpected error %v", diags)
+			}
+
+			if cidrs := d.Get("cidrs"); !reflect.DeepEqual(cidrs, test.expected) {
+				t.Errorf("Expected %v but got %v", test.expected, cidrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package pfsense
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceFirewallRule() *schema.Resource {
+	r := resourceFirewallRule()
+	schemas := r.dataSourceSchema()
+
+	schemas["tracker"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Required:    true,
+		Description: "Tracker of the rule, the ID of its `pfsense_firewall_rule`.",
+	}
+
+	return &schema.Resource{
+		Description: "Reads an existing firewall rule, e.g. to use it as a template for `pfsense_firewall_rule` resources on other interfaces. " +
+			"The attributes are the arguments of `pfsense_firewall_rule`.",
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			d.SetId(strconv.Itoa(d.Get("tracker").(int)))
+
+			if err := r.UpdateFromId(ctx, m.(*providerClient), d); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		Schema: schemas,
+	}
+}
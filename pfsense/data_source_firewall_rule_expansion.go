@@ -0,0 +1,164 @@
+package pfsense
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+// expandAliasEntries replaces the value with the entries of the alias it names, following nested aliases. Values that
+// aren't aliases, e.g. addresses, ports and interfaces, are returned as they are. The alias must be one of the types.
+func expandAliasEntries(aliases []*pfsenseapi.FirewallAlias, value string, types []string) ([]string, error) {
+	w := newAliasWalk(aliases)
+
+	if _, ok := w.aliases[value]; !ok {
+		return []string{value}, nil
+	}
+
+	var entries []string
+	seen := map[string]struct{}{}
+
+	w.alias = func(alias *pfsenseapi.FirewallAlias) error {
+		if !slices.Contains(types, alias.Type) {
+			return fmt.Errorf("Alias %s is a %s alias, expected %s", alias.Name, alias.Type, strings.Join(types, " or "))
+		}
+
+		return nil
+	}
+
+	w.entry = func(_ string, entry string) error {
+		if _, ok := seen[entry]; !ok {
+			seen[entry] = struct{}{}
+			entries = append(entries, entry)
+		}
+
+		return nil
+	}
+
+	if err := w.walk(nil, []string{value}); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func dataSourceFirewallRuleExpansion() *schema.Resource {
+	return &schema.Resource{
+		Description: "Expands the source and destination of a firewall rule to the addresses and ports they cover, following nested aliases, e.g. to review what a rule allows before applying it. Addresses, ports, interfaces and hostnames are returned as they are, hostnames aren't resolved.",
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+
+			aliases, err := client.aliases.ListAliases(ctx)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			for _, name := range []string{"source", "destination"} {
+				value := d.Get(name).(string)
+				entries, err := expandAliasEntries(aliases, strings.TrimPrefix(value, "!"), []string{"host", "network"})
+
+				if err != nil {
+					return diag.Errorf("Unable to expand %s: %v", name, err)
+				}
+
+				if err := d.Set(name+"_addresses", entries); err != nil {
+					return diag.FromErr(err)
+				}
+
+				if err := d.Set(name+"_negated", strings.HasPrefix(value, "!")); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+
+			for _, name := range []string{"source_port", "destination_port"} {
+				entries, err := expandAliasEntries(aliases, normalizePort(d.Get(name).(string)), []string{"port"})
+
+				if err != nil {
+					return diag.Errorf("Unable to expand %s: %v", name, err)
+				}
+
+				if err := d.Set(name+"s", entries); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+
+			d.SetId(strings.Join([]string{d.Get("source").(string), d.Get("source_port").(string), d.Get("destination").(string), d.Get("destination_port").(string)}, "/"))
+
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "any",
+				Description: "Source address of the rule, as it's set on `pfsense_firewall_rule`.",
+			},
+			"source_port": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "any",
+				Description: "Source port of the rule, as it's set on `pfsense_firewall_rule`.",
+			},
+			"destination": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "any",
+				Description: "Destination address of the rule, as it's set on `pfsense_firewall_rule`.",
+			},
+			"destination_port": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "any",
+				Description: "Destination port of the rule, as it's set on `pfsense_firewall_rule`.",
+			},
+			"source_addresses": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Addresses, networks, ranges, hostnames or interfaces the source covers.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"source_negated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the source is negated with `!`, the rule then matches everything but `source_addresses`.",
+			},
+			"source_ports": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Ports and port ranges the source port covers.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"destination_addresses": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Addresses, networks, ranges, hostnames or interfaces the destination covers.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"destination_negated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the destination is negated with `!`, the rule then matches everything but `destination_addresses`.",
+			},
+			"destination_ports": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Ports and port ranges the destination port covers.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package pfsense
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_dataSourceFirewallRuleExpansion(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/alias": `[
+			{"name": "web_servers", "type": "host", "address": "10.0.0.10 10.0.0.11"},
+			{"name": "dmz", "type": "network", "address": "web_servers 10.0.1.0/24 10.0.0.10"},
+			{"name": "web_ports", "type": "port", "address": "80 443 alt_ports"},
+			{"name": "alt_ports", "type": "port", "address": "8000:8080"},
+			{"name": "loop_a", "type": "host", "address": "loop_b"},
+			{"name": "loop_b", "type": "host", "address": "loop_a"}
+		]`,
+	})
+
+	tests := map[string]struct {
+		config   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"defaults": {
+			config: map[string]interface{}{},
+			expected: map[string]interface{}{
+				"source_addresses":      []interface{}{"any"},
+				"source_ports":          []interface{}{"any"},
+				"destination_addresses": []interface{}{"any"},
+				"destination_ports":     []interface{}{"any"},
+			},
+		},
+		"nested aliases": {
+			config: map[string]interface{}{"source": "lan", "destination": "!dmz", "destination_port": "web_ports"},
+			expected: map[string]interface{}{
+				"source_addresses":      []interface{}{"lan"},
+				"destination_addresses": []interface{}{"10.0.0.10", "10.0.0.11", "10.0.1.0/24"},
+				"destination_negated":   true,
+				"destination_ports":     []interface{}{"80", "443", "8000:8080"},
+			},
+		},
+		"port alias as address": {config: map[string]interface{}{"destination": "web_ports"}},
+		"host alias as port":    {config: map[string]interface{}{"destination_port": "web_servers"}},
+		"loop":                  {config: map[string]interface{}{"source": "loop_a"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := dataSourceFirewallRuleExpansion()
+			d := schema.TestResourceDataRaw(t, r.Schema, test.config)
+			diags := r.ReadContext(context.Background(), d, client)
+
+			if test.expected == nil {
+				if !diags.HasError() {
+					t.Errorf("Expected an error for %v", test.config)
+				}
+
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("Unexpected error %v", diags)
+			}
+
+			for key, expected := range test.expected {
+				if actual := d.Get(key); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("Expected %s to be %v but got %v", key, expected, actual)
+				}
+			}
+		})
+	}
+}
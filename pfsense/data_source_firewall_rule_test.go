@@ -0,0 +1,72 @@
+package pfsense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func Test_dataSourceFirewallRule(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/rule": `[{
+			"tracker": "1700000001",
+			"type": "pass",
+			"interface": "lan",
+			"ipprotocol": "inet",
+			"protocol": "tcp",
+			"source": {"address": "10.0.0.0/24"},
+			"destination": {"address": "WEB_SERVERS", "port": "443"},
+			"descr": "Allow web traffic",
+			"tcpflags1": "syn",
+			"tcpflags2": "syn,ack"
+		}]`,
+	})
+
+	r := dataSourceFirewallRule()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"tracker": 1700000002})
+
+	if diags := r.ReadContext(context.Background(), d, client); !diags.HasError() {
+		t.Errorf("Expected an error for a rule that doesn't exist")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"tracker": 1700000001})
+
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	expected := map[string]interface{}{
+		"interface.0":      "lan",
+		"protocol":         "tcp",
+		"destination":      "WEB_SERVERS",
+		"destination_port": "443",
+		"description":      "Allow web traffic",
+		"tcp_flag.#":       2,
+	}
+
+	for name, value := range expected {
+		if actual := d.Get(name); actual != value {
+			t.Errorf("Expected %s to be %v but got %v", name, value, actual)
+		}
+	}
+
+	// The attributes are used as the arguments of a new rule, so they have to be valid config for the resource
+	res := Provider().ResourcesMap["pfsense_firewall_rule"]
+	config := map[string]interface{}{}
+
+	for name, property := range res.Schema {
+		if value, ok := d.GetOk(name); ok && (property.Optional || property.Required) {
+			if set, ok := value.(*schema.Set); ok {
+				value = set.List()
+			}
+
+			config[name] = value
+		}
+	}
+
+	if diags := res.Validate(terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Errorf("Expected the attributes to be valid config but got %v", diags)
+	}
+}
@@ -0,0 +1,87 @@
+package pfsense
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+// findAlias returns the alias with the name or nil if there isn't one, pfSense compares alias names ignoring case
+func findAlias(aliases []*pfsenseapi.FirewallAlias, name string) *pfsenseapi.FirewallAlias {
+	for _, alias := range aliases {
+		if strings.EqualFold(alias.Name, name) {
+			return alias
+		}
+	}
+
+	return nil
+}
+
+func dataSourceHostPortAlias() *schema.Resource {
+	return &schema.Resource{
+		Description: "Pairs a host or network alias with a port alias for use on the same firewall rule, e.g. as `destination` and `destination_port`. Fails if either alias doesn't exist or is the wrong type.",
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+
+			aliases, err := client.aliases.ListAliases(ctx)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			hostAlias := d.Get("host_alias").(string)
+			portAlias := d.Get("port_alias").(string)
+
+			if alias := findAlias(aliases, hostAlias); alias == nil {
+				return diag.Errorf("Unable to find alias %s", hostAlias)
+			} else if !slices.Contains([]string{"host", "network"}, alias.Type) {
+				return diag.Errorf("Alias %s is a %s alias, host_alias must be a host or network alias", hostAlias, alias.Type)
+			}
+
+			if alias := findAlias(aliases, portAlias); alias == nil {
+				return diag.Errorf("Unable to find alias %s", portAlias)
+			} else if alias.Type != "port" {
+				return diag.Errorf("Alias %s is a %s alias, port_alias must be a port alias", portAlias, alias.Type)
+			}
+
+			if err := d.Set("address", hostAlias); err != nil {
+				return diag.FromErr(err)
+			}
+
+			if err := d.Set("port", portAlias); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(fmt.Sprintf("%s:%s", hostAlias, portAlias))
+
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"host_alias": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of an existing host or network alias.",
+			},
+			"port_alias": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of an existing port alias.",
+			},
+			"address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the host or network alias, for a rule's `source` or `destination`.",
+			},
+			"port": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the port alias, for a rule's `source_port` or `destination_port`.",
+			},
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package pfsense
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_dataSourceHostPortAlias(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/alias": `[
+			{"name": "web_servers", "type": "host", "address": "10.0.0.10"},
+			{"name": "web_networks", "type": "network", "address": "10.0.0.0/24"},
+			{"name": "web_ports", "type": "port", "address": "80 443"}
+		]`,
+	})
+
+	tests := map[string]struct {
+		hostAlias string
+		portAlias string
+		valid     bool
+	}{
+		"host":            {"web_servers", "web_ports", true},
+		"network":         {"web_networks", "web_ports", true},
+		"swapped":         {"web_ports", "web_servers", false},
+		"port as host":    {"web_ports", "web_ports", false},
+		"missing host":    {"missing", "web_ports", false},
+		"missing port":    {"web_servers", "missing", false},
+		"host as port":    {"web_servers", "web_networks", false},
+		"network as port": {"web_networks", "web_networks", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := dataSourceHostPortAlias()
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+				"host_alias": test.hostAlias,
+				"port_alias": test.portAlias,
+			})
+
+			diags := r.ReadContext(context.Background(), d, client)
+
+			if test.valid == diags.HasError() {
+				t.Fatalf("Expected valid to be %v but got %v", test.valid, diags)
+			}
+
+			if test.valid && (d.Get("address") != test.hostAlias || d.Get("port") != test.portAlias) {
+				t.Errorf("Expected %s and %s but got %v and %v", test.hostAlias, test.portAlias, d.Get("address"), d.Get("port"))
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package pfsense
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+func dataSourceRemoteAliases() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads every alias from another pfSense, connecting with its own settings rather than the provider's, e.g. to mirror a primary's aliases onto a secondary with `for_each` over `pfsense_firewall_alias` resources.",
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			c, err := newClientConfig(d)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			aliases, err := pfsenseapi.NewClient(c).Firewall.ListAliases(ctx)
+
+			if err != nil {
+				return diag.Errorf("Unable to list aliases on %s: %v", c.Host, err)
+			}
+
+			result := []interface{}{}
+
+			for _, alias := range aliases {
+				targets := []interface{}{}
+				details := splitIntoArray(alias.Detail, detailSplitter)
+
+				for i, address := range splitIntoArray(alias.Address, addressSplitter) {
+					target := map[string]interface{}{"address": address}
+
+					if len(details) > i {
+						target["description"] = details[i]
+					}
+
+					targets = append(targets, target)
+				}
+
+				result = append(result, map[string]interface{}{
+					"name":        alias.Name,
+					"type":        alias.Type,
+					"description": alias.Descr,
+					"target":      targets,
+				})
+			}
+
+			if err := d.Set("aliases", result); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(c.Host)
+
+			return nil
+		},
+		Schema: connectionSchema(map[string]*schema.Schema{
+			"aliases": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The aliases on the remote pfSense, with the same attributes as `pfsense_firewall_alias`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the alias.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of the alias.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description of the alias.",
+						},
+						"target": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Hosts, networks or port values in the alias.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"address": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "Host, network or port value.",
+									},
+									"description": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "Description of the address.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}),
+	}
+}
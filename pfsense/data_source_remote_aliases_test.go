@@ -0,0 +1,67 @@
+package pfsense
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func Test_dataSourceRemoteAliases(t *testing.T) {
+	// The remote pfSense is a separate server from the provider's, which isn't configured at all
+	remote, requests := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/alias": `[
+			{"name": "web_servers", "type": "host", "address": "10.0.0.10 10.0.0.11", "descr": "Web", "detail": "web1||web2"},
+			{"name": "web_ports", "type": "port", "address": "443"}
+		]`,
+	})
+
+	r := dataSourceRemoteAliases()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"url":           remote.Cfg.Host,
+		"api_client_id": "mirror",
+	})
+
+	if diags := r.ReadContext(context.Background(), d, nil); !diags.HasError() {
+		t.Errorf("Expected an error without api_client_token")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"url":              remote.Cfg.Host,
+		"api_client_id":    "mirror",
+		"api_client_token": "secret",
+	})
+
+	if diags := r.ReadContext(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":        "web_servers",
+			"type":        "host",
+			"description": "Web",
+			"target": []interface{}{
+				map[string]interface{}{"address": "10.0.0.10", "description": "web1"},
+				map[string]interface{}{"address": "10.0.0.11", "description": "web2"},
+			},
+		},
+		map[string]interface{}{
+			"name":        "web_ports",
+			"type":        "port",
+			"description": "",
+			"target": []interface{}{
+				map[string]interface{}{"address": "443", "description": ""},
+			},
+		},
+	}
+
+	if aliases := d.Get("aliases"); !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("Expected %v but got %v", expected, aliases)
+	}
+
+	if len(*requests) != 1 {
+		t.Errorf("Expected the remote aliases to be listed once but got %v", *requests)
+	}
+}
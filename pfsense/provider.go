@@ -15,12 +15,19 @@
 //     api_client_token  = "your_client_token"       // Optional: For token auth.
 //     skip_tls          = false                     // Optional: Default is false.
 //     timeout           = 30                        // Optional: Default is 30 seconds.
+//     auto_reload       = true                      // Optional: Default is true.
+//     allow_reboot      = false                     // Optional: Default is false.
+//     require_unique_rule_descriptions = false      // Optional: Default is false.
+//     dry_run           = false                     // Optional: Default is false.
 // }
 //
 // Notes:
 // - JWTAuthEnabled is inferred from the presence of `jwt_token`.
 // - LocalAuthEnabled is inferred from the presence of `user`.
 // - TokenAuthEnabled is inferred from the presence of `api_client_id`.
+// - When `auto_reload` is false changes are only applied by a `pfsense_commit` resource.
+// - `pfsense_system_reboot` resources can only reboot the firewall when `allow_reboot` is true.
+// - When `dry_run` is true nothing is written, applies fail with the requests that would have been sent.
 //
 // Created by: [Your Name or Alias]
 // Date: [Creation Date]
@@ -30,8 +37,11 @@
 package pfsense
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -42,54 +52,41 @@ import (
 // Provider returns a Terraform provider for managing pfSense resources.
 func Provider() *schema.Provider {
 	provider := &schema.Provider{
-		Schema: map[string]*schema.Schema{
-			"url": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
-				Description:  "The url of the target pfsense e.g https://192.168.1.1",
-			},
-			"user": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Local authentication username.",
-			},
-			"password": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Local authentication password.",
-				Sensitive:   true,
-			},
-			"jwt_token": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "JWT token for authentication.",
-				Sensitive:   true,
-			},
-			"api_client_id": {
-				Type:        schema.TypeString,
+		Schema: connectionSchema(map[string]*schema.Schema{
+			"auto_reload": {
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "API Client ID for token-based authentication.",
+				Description: "Apply changes (filter reload, interface reconfiguration) as each resource is changed. Set to `false` to defer them to a `pfsense_commit` resource.",
+				Default:     true,
 			},
-			"api_client_token": {
-				Type:        schema.TypeString,
+			"allow_reboot": {
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "API Client Token for token-based authentication.",
-				Sensitive:   true,
+				Description: "Allow `pfsense_system_reboot` resources to reboot the firewall. Creating one fails unless this is `true`.",
+				Default:     false,
 			},
-			"allow_insecure": {
+			"require_unique_rule_descriptions": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Skip TLS verification. If not specified, it defaults to true unless the url uses HTTPS.",
+				Description: "Require every `pfsense_firewall_rule` to have a description (or labels) that no other rule on the same interface has, checked at plan time.",
+				Default:     false,
 			},
-			"timeout": {
-				Type:        schema.TypeInt,
+			"dry_run": {
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Request timeout duration in seconds.",
-				Default:     60,
+				Description: "Validate and build each request without sending it. Reads still go to pfSense, but every create, update and delete fails with the request that would have been sent, so applies make no changes and leave the plan as it was. `pfsense_commit` and `pfsense_system_reboot` fail the same way.",
+				Default:     false,
 			},
+		}),
+		ResourcesMap: map[string]*schema.Resource{},
+		DataSourcesMap: map[string]*schema.Resource{
+			"pfsense_cloud_provider_ranges":   dataSourceCloudProviderRanges(),
+			"pfsense_firewall_alias_cidrs":    dataSourceFirewallAliasCidrs(),
+			"pfsense_firewall_rule":           dataSourceFirewallRule(),
+			"pfsense_firewall_rule_expansion": dataSourceFirewallRuleExpansion(),
+			"pfsense_host_port_alias":         dataSourceHostPortAlias(),
+			"pfsense_remote_aliases":          dataSourceRemoteAliases(),
 		},
-		ResourcesMap:  map[string]*schema.Resource{},
 		ConfigureFunc: providerConfigure,
 	}
 
@@ -101,10 +98,204 @@ func Provider() *schema.Provider {
 	resourceInterfaceVLAN().AddResource(provider)
 	resourceUnboundHostOverride().AddResource(provider)
 
+	provider.ResourcesMap["pfsense_commit"] = resourceCommit()
+	provider.ResourcesMap["pfsense_firewall_rule_bulk_state"] = resourceFirewallRuleBulkState()
+	provider.ResourcesMap["pfsense_interface_state"] = resourceInterfaceState()
+	provider.ResourcesMap["pfsense_system_reboot"] = resourceSystemReboot()
+
 	return provider
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+// providerClient is the provider meta, it carries provider wide settings along with the API client.
+type providerClient struct {
+	*pfsenseapi.Client
+	autoReload                    bool
+	allowReboot                   bool
+	requireUniqueRuleDescriptions bool
+	dryRun                        bool
+	aliases                       aliasClient
+	// largeAliases is used to write aliases with at least largeAliasSize entries, pfSense can take longer than the
+	// request timeout to save and reload them
+	largeAliases aliasClient
+	// aliasLocks serializes writes to the same alias, the API replaces the whole alias on each write
+	aliasLocks namedLocks
+	// reloads serializes writes that reload the filter, see lockReload
+	reloads sync.Mutex
+	rules   listCache[pfsenseapi.FirewallRule]
+	// aliasCache is the alias list rules are checked against while planning, alias writes invalidate it
+	aliasCache listCache[pfsenseapi.FirewallAlias]
+	// interfaceCache and interfaceGroupCache are the interfaces rules are checked against, interface writes
+	// invalidate them
+	interfaceCache      listCache[pfsenseapi.Interface]
+	interfaceGroupCache listCache[pfsenseapi.InterfaceGroup]
+}
+
+// invalidateInterfaces drops the cached interfaces and interface groups after an interface is written
+func (c *providerClient) invalidateInterfaces() {
+	c.interfaceCache.invalidate()
+	c.interfaceGroupCache.invalidate()
+}
+
+const largeAliasTimeout = 10 * time.Minute
+
+func newProviderClient(client *pfsenseapi.Client, autoReload bool) *providerClient {
+	largeAliasConfig := client.Cfg
+	largeAliasConfig.Timeout = max(client.Cfg.Timeout, largeAliasTimeout)
+
+	return &providerClient{
+		Client:       client,
+		autoReload:   autoReload,
+		aliases:      client.Firewall,
+		largeAliases: pfsenseapi.NewClient(largeAliasConfig).Firewall,
+	}
+}
+
+// lockReload serializes the writes that reload the filter when reload is true. pfSense reloads the filter as part of
+// each of those requests, with Terraform's parallelism the reloads would overlap.
+func (c *providerClient) lockReload(reload bool) func() {
+	if !reload {
+		return func() {}
+	}
+
+	c.reloads.Lock()
+
+	return c.reloads.Unlock
+}
+
+// listCache keeps the result of a list call for the rest of the run so each resource read doesn't fetch it again,
+// it has to be invalidated by any write.
+type listCache[T any] struct {
+	mutex sync.Mutex
+	items []*T
+}
+
+func (c *listCache[T]) get(ctx context.Context, list func(context.Context) ([]*T, error)) ([]*T, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.items == nil {
+		items, err := list(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		c.items = items
+	}
+
+	return c.items, nil
+}
+
+func (c *listCache[T]) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.items = nil
+}
+
+// namedLocks hands out a mutex per name so operations on the same object serialize while different objects proceed
+// in parallel.
+type namedLocks struct {
+	mutex sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock acquires the locks for every name, always in the same order so callers locking several names can't deadlock,
+// and returns a function that releases them.
+func (l *namedLocks) lock(names ...string) func() {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	l.mutex.Lock()
+
+	if l.locks == nil {
+		l.locks = map[string]*sync.Mutex{}
+	}
+
+	locks := make([]*sync.Mutex, len(names))
+
+	for i, name := range names {
+		if _, ok := l.locks[name]; !ok {
+			l.locks[name] = &sync.Mutex{}
+		}
+
+		locks[i] = l.locks[name]
+	}
+
+	l.mutex.Unlock()
+
+	for _, lock := range locks {
+		lock.Lock()
+	}
+
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+}
+
+// connectionSchema adds the settings needed to connect to a pfSense to the schema, they're shared by the provider and
+// anything else that connects to a pfSense of its own.
+func connectionSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	connection := map[string]*schema.Schema{
+		"url": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			Description:  "The url of the target pfsense e.g https://192.168.1.1",
+		},
+		"user": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Local authentication username.",
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Local authentication password.",
+			Sensitive:   true,
+		},
+		"jwt_token": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "JWT token for authentication.",
+			Sensitive:   true,
+		},
+		"api_client_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "API Client ID for token-based authentication.",
+		},
+		"api_client_token": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "API Client Token for token-based authentication.",
+			Sensitive:   true,
+		},
+		"allow_insecure": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Skip TLS verification. If not specified, it defaults to true unless the url uses HTTPS.",
+		},
+		"timeout": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Request timeout duration in seconds.",
+			Default:     60,
+		},
+	}
+
+	for name, property := range connection {
+		s[name] = property
+	}
+
+	return s
+}
+
+// newClientConfig builds the client configuration from the connection settings in connectionSchema
+func newClientConfig(d *schema.ResourceData) (pfsenseapi.Config, error) {
 	url := d.Get("url").(string)
 
 	d.Get("allow_insecure")
@@ -128,7 +319,7 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		c.User = user.(string)
 
 		if password, ok := d.GetOk("password"); !ok {
-			return nil, errors.New("password is required when username is provided")
+			return pfsenseapi.Config{}, errors.New("password is required when username is provided")
 		} else {
 			c.Password = password.(string)
 		}
@@ -140,7 +331,7 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		c.ApiClientID = clientID.(string)
 
 		if clientToken, ok := d.GetOk("api_client_token"); !ok {
-			return nil, errors.New("api_client_token is required when api_client_id is provided")
+			return pfsenseapi.Config{}, errors.New("api_client_token is required when api_client_id is provided")
 		} else {
 			c.ApiClientToken = clientToken.(string)
 		}
@@ -159,9 +350,23 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	}
 
 	if authCount > 1 {
-		return nil, errors.New("only one form of authentication should be provided")
+		return pfsenseapi.Config{}, errors.New("only one form of authentication should be provided")
 	}
 
-	client := pfsenseapi.NewClient(c)
+	return c, nil
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	c, err := newClientConfig(d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client := newProviderClient(pfsenseapi.NewClient(c), d.Get("auto_reload").(bool))
+	client.allowReboot = d.Get("allow_reboot").(bool)
+	client.requireUniqueRuleDescriptions = d.Get("require_unique_rule_descriptions").(bool)
+	client.dryRun = d.Get("dry_run").(bool)
+
 	return client, nil
 }
@@ -44,6 +44,22 @@ func Test_AllPropertiesAndResourcesAreDocumented(t *testing.T) {
 	}
 }
 
+func Test_AllDataSourcesAreDocumented(t *testing.T) {
+	p := Provider()
+
+	for name, dataSource := range p.DataSourcesMap {
+		if dataSource.Description == "" {
+			t.Errorf("Data source %s has no documentation", name)
+		}
+
+		for property, schema := range dataSource.Schema {
+			if schema.Description == "" {
+				t.Errorf("Property %s on data source %s has no documentation", property, name)
+			}
+		}
+	}
+}
+
 func Test_runResourceTests(t *testing.T) {
 	p := Provider()
 
@@ -55,6 +71,10 @@ func Test_runResourceTests(t *testing.T) {
 		resourceInterfaceTest(),
 		resourceInterfaceVLANTest(),
 		resourceUnboundHostOverrideTest(),
+		resourceCommitTest(),
+		resourceFirewallRuleBulkStateTest(),
+		resourceInterfaceStateTest(),
+		resourceSystemRebootTest(),
 	}
 
 	resourceMap := map[string]resourceTest{}
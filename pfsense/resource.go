@@ -2,6 +2,7 @@ package pfsense
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -10,7 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
 const idSeparator = "."
@@ -18,10 +18,10 @@ const idSeparator = "."
 type updateRequestFunc[RequestType any] func(*schema.ResourceData, string, *RequestType) error
 type getFromResourceFunc[ResponseType any] func(*ResponseType) (interface{}, error)
 
-type updateFunc[RequestType any, ResponseType any, IdType ~string | ~int] func(context.Context, *pfsenseapi.Client, IdType, *RequestType) (*ResponseType, error)
-type createFunc[RequestType any, ResponseType any] func(context.Context, *pfsenseapi.Client, *RequestType) (*ResponseType, error)
-type listFunc[ResponseType any] func(context.Context, *pfsenseapi.Client, string) ([]*ResponseType, error)
-type deleteFunc[IdType ~string | ~int] func(context.Context, *pfsenseapi.Client, string, IdType) error
+type updateFunc[RequestType any, ResponseType any, IdType ~string | ~int] func(context.Context, *providerClient, IdType, *RequestType) (*ResponseType, error)
+type createFunc[RequestType any, ResponseType any] func(context.Context, *providerClient, *RequestType) (*ResponseType, error)
+type listFunc[ResponseType any] func(context.Context, *providerClient, string) ([]*ResponseType, error)
+type deleteFunc[IdType ~string | ~int] func(context.Context, *providerClient, string, IdType) error
 type disableFunc[RequestType any] func(*RequestType) error
 
 var dnsValidator schema.SchemaValidateFunc = validation.StringMatch(regexValidator(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,6}$`), "Invalid DNS Name")
@@ -33,20 +33,32 @@ type resourceProperty[RequestType any, ResponseType any] struct {
 	partition       bool
 	updateRequest   updateRequestFunc[RequestType]
 	getFromResponse getFromResourceFunc[ResponseType]
+	diffSuppress    schema.SchemaDiffSuppressFunc
 	validValues     []string
 }
 
 type resource[RequestType any, ResponseType any, IdType ~string | ~int] struct {
-	name        string
-	description string
-	getId       func(context.Context, *pfsenseapi.Client, *ResponseType) (IdType, error)
-	partitionId string
-	update      updateFunc[RequestType, ResponseType, IdType]
-	create      createFunc[RequestType, ResponseType]
-	delete      deleteFunc[IdType]
-	disable     disableFunc[RequestType]
-	list        listFunc[ResponseType]
-	properties  map[string]*resourceProperty[RequestType, ResponseType]
+	name          string
+	description   string
+	getId         func(context.Context, *providerClient, *ResponseType) (IdType, error)
+	partitionId   string
+	update        updateFunc[RequestType, ResponseType, IdType]
+	create        createFunc[RequestType, ResponseType]
+	delete        deleteFunc[IdType]
+	disable       disableFunc[RequestType]
+	list          listFunc[ResponseType]
+	customizeDiff schema.CustomizeDiffFunc
+	beforeDelete  func(context.Context, *providerClient, *schema.ResourceData, IdType) error
+	warnings      func(*schema.ResourceData) diag.Diagnostics
+	importId      func(context.Context, *providerClient, string) (string, error)
+	timeouts      *schema.ResourceTimeout
+	// schemaVersion and stateUpgraders move state written by older versions of the provider to the current schema
+	schemaVersion  int
+	stateUpgraders []schema.StateUpgrader
+	// clearEmptyStrings reads empty strings in the response as "" for properties without a default, otherwise a value
+	// cleared outside Terraform is left in the state
+	clearEmptyStrings bool
+	properties        map[string]*resourceProperty[RequestType, ResponseType]
 }
 
 func (r *resource[RequestType, ResponseType, IdType]) updateRequest(d *schema.ResourceData, request *RequestType) error {
@@ -65,7 +77,7 @@ func (r *resource[RequestType, ResponseType, IdType]) updateRequest(d *schema.Re
 			exists = true
 		}
 
-		if exists {
+		if exists && prop.updateRequest != nil {
 			if err := prop.updateRequest(d, name, request); err != nil {
 				return err
 			}
@@ -95,24 +107,136 @@ func (r *resource[RequestType, ResponseType, IdType]) updateResource(d *schema.R
 			}
 		} else if prop.schema.Default != nil {
 			d.Set(name, prop.schema.Default)
+		} else if r.clearEmptyStrings && prop.schema.Type == schema.TypeString {
+			if err = d.Set(name, ""); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// requestFromResponse builds the request that would write the response back unchanged, e.g. to change one setting of
+// an object the provider doesn't manage
+func (r *resource[RequestType, ResponseType, IdType]) requestFromResponse(response *ResponseType) (*RequestType, error) {
+	schemas := map[string]*schema.Schema{}
+
+	for name, property := range r.properties {
+		schemas[name] = property.schema
+	}
+
+	d := (&schema.Resource{Schema: schemas}).Data(nil)
+
+	if err := r.updateResource(d, response); err != nil {
+		return nil, err
+	}
+
+	request := new(RequestType)
+
+	if err := r.updateRequest(d, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// dataSourceSchema is the resource's schema with every property computed, for a data source that reads an existing item
+func (r *resource[RequestType, ResponseType, IdType]) dataSourceSchema() map[string]*schema.Schema {
+	schemas := map[string]*schema.Schema{}
+
+	for name, property := range r.properties {
+		schemas[name] = computedSchema(property.schema)
+	}
+
+	return schemas
+}
+
+// computedSchema copies the schema as a computed attribute, without the defaults and validation that only apply to
+// config
+func computedSchema(s *schema.Schema) *schema.Schema {
+	computed := &schema.Schema{
+		Type:        s.Type,
+		Computed:    true,
+		Sensitive:   s.Sensitive,
+		Description: s.Description,
+		Set:         s.Set,
+	}
+
+	switch elem := s.Elem.(type) {
+	case *schema.Schema:
+		computed.Elem = &schema.Schema{Type: elem.Type}
+	case *schema.Resource:
+		nested := map[string]*schema.Schema{}
+
+		for name, s := range elem.Schema {
+			nested[name] = computedSchema(s)
+		}
+
+		computed.Elem = &schema.Resource{Schema: nested}
+	}
+
+	return computed
+}
+
+// getWarnings returns the warnings for the resource once it's been created or updated, they don't stop the apply.
+func (r *resource[RequestType, ResponseType, IdType]) getWarnings(d *schema.ResourceData) diag.Diagnostics {
+	if r.warnings == nil {
+		return nil
+	}
+
+	return r.warnings(d)
+}
+
+// dryRunDiagnostics reports the write that would have been made in dry run mode. It's an error so Terraform doesn't
+// record a change that wasn't made.
+func (r *resource[RequestType, ResponseType, IdType]) dryRunDiagnostics(action string, id string, request *RequestType) diag.Diagnostics {
+	summary := fmt.Sprintf("Dry run, %s not %s", r.name, action)
+
+	if id != "" {
+		summary = fmt.Sprintf("Dry run, %s %s not %s", r.name, id, action)
+	}
+
+	if request == nil {
+		return diag.Diagnostics{{Severity: diag.Error, Summary: summary}}
+	}
+
+	payload, err := json.MarshalIndent(request, "", "  ")
+
+	if err != nil {
+		return diag.Errorf("%s, unable to format the request: %v", summary, err)
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("The provider has dry_run set, this request would have been sent:\n%s", payload),
+	}}
+}
+
 func (r *resource[RequestType, ResponseType, IdType]) GetCreateFunction() schema.CreateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-		client := m.(*pfsenseapi.Client)
+		client := m.(*providerClient)
 		request := new(RequestType)
 
 		if err := r.updateRequest(d, request); err != nil {
 			return diag.FromErr(err)
 		}
 
+		if client.dryRun {
+			return r.dryRunDiagnostics("created", "", request)
+		}
+
 		response, err := r.create(ctx, client, request)
 
 		if err != nil {
+			// A response along with the error means the item was created and something after failed, e.g. waiting
+			// for it to come up. Keeping the ID has Terraform taint the resource rather than lose track of it. The
+			// failure can be the timeout, so the ID is looked up without it.
+			if response != nil && r.setId(context.WithoutCancel(ctx), client, d, response) == nil {
+				_ = r.updateResource(d, response)
+			}
+
 			return diag.FromErr(err)
 		}
 
@@ -120,39 +244,59 @@ func (r *resource[RequestType, ResponseType, IdType]) GetCreateFunction() schema
 			return diag.FromErr(err)
 		}
 
-		id, err := r.getId(ctx, client, response)
-
-		if err != nil {
+		if err := r.setId(ctx, client, d, response); err != nil {
 			return diag.FromErr(err)
 		}
 
-		if reflect.ValueOf(id).IsZero() {
-			return diag.Errorf("Invalid ID returned for %s: '%s'", r.name, fmt.Sprint(id))
-		}
+		return r.getWarnings(d)
+	}
+}
 
-		if r.partitionId != "" {
-			i, ok := d.GetOk(r.partitionId)
+// setId sets the resource ID from the response, prefixed with the partition when the resource has one
+func (r *resource[RequestType, ResponseType, IdType]) setId(ctx context.Context, client *providerClient, d *schema.ResourceData, response *ResponseType) error {
+	id, err := r.getId(ctx, client, response)
 
-			if !ok {
-				return diag.Errorf("Field %s is required, provider error, should be already validated", r.partitionId)
-			}
+	if err != nil {
+		return err
+	}
 
-			parition, ok := i.(string)
+	if reflect.ValueOf(id).IsZero() {
+		return fmt.Errorf("Invalid ID returned for %s: '%s'", r.name, fmt.Sprint(id))
+	}
 
-			if !ok {
-				return diag.Errorf("Field %s should be a string, provider error, should be already validated", r.partitionId)
-			}
+	if r.partitionId != "" {
+		i, ok := d.GetOk(r.partitionId)
 
-			d.SetId(fmt.Sprintf("%s%s%s", parition, idSeparator, fmt.Sprint(id)))
-		} else {
-			d.SetId(fmt.Sprint(id))
+		if !ok {
+			return fmt.Errorf("Field %s is required, provider error, should be already validated", r.partitionId)
 		}
 
-		return nil
+		parition, ok := i.(string)
+
+		if !ok {
+			return fmt.Errorf("Field %s should be a string, provider error, should be already validated", r.partitionId)
+		}
+
+		d.SetId(fmt.Sprintf("%s%s%s", parition, idSeparator, fmt.Sprint(id)))
+	} else {
+		d.SetId(fmt.Sprint(id))
 	}
+
+	return nil
 }
 
-func (r *resource[RequestType, ResponseType, IdType]) UpdateFromId(ctx context.Context, client *pfsenseapi.Client, d *schema.ResourceData) error {
+// idChanged is true when the resource's ID comes from a property that's being changed in place, e.g. an alias rename
+func (r *resource[RequestType, ResponseType, IdType]) idChanged(d *schema.ResourceData) bool {
+	for name, property := range r.properties {
+		if property.idProperty && d.HasChange(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *resource[RequestType, ResponseType, IdType]) UpdateFromId(ctx context.Context, client *providerClient, d *schema.ResourceData) error {
 	var list []*ResponseType
 	var err error
 
@@ -202,7 +346,7 @@ func (r *resource[RequestType, ResponseType, IdType]) UpdateFromId(ctx context.C
 
 func (r *resource[RequestType, ResponseType, IdType]) GetReadFunction() schema.ReadContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-		client := m.(*pfsenseapi.Client)
+		client := m.(*providerClient)
 		if err := r.UpdateFromId(ctx, client, d); err != nil {
 			return diag.FromErr(err)
 		}
@@ -245,7 +389,7 @@ func (r *resource[RequestType, ResponseType, IdType]) getResourceId(d *schema.Re
 
 func (r *resource[RequestType, ResponseType, IdType]) GetUpdateFunction() schema.UpdateContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-		client := m.(*pfsenseapi.Client)
+		client := m.(*providerClient)
 		request := new(RequestType)
 
 		if err := r.updateRequest(d, request); err != nil {
@@ -258,6 +402,12 @@ func (r *resource[RequestType, ResponseType, IdType]) GetUpdateFunction() schema
 			return diag.FromErr(err)
 		}
 
+		if client.dryRun {
+			// Without partial mode the planned values are saved to state even though the update fails
+			d.Partial(true)
+			return r.dryRunDiagnostics("updated", d.Id(), request)
+		}
+
 		response, err := r.update(ctx, client, id, request)
 
 		if err != nil {
@@ -268,13 +418,19 @@ func (r *resource[RequestType, ResponseType, IdType]) GetUpdateFunction() schema
 			return diag.FromErr(err)
 		}
 
-		return nil
+		if r.idChanged(d) {
+			if err := r.setId(ctx, client, d, response); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return r.getWarnings(d)
 	}
 }
 
 func (r *resource[RequestType, ResponseType, IdType]) GetDeleteFunction() schema.DeleteContextFunc {
 	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-		client := m.(*pfsenseapi.Client)
+		client := m.(*providerClient)
 
 		partition, id, err := r.getResourceId(d)
 
@@ -282,6 +438,16 @@ func (r *resource[RequestType, ResponseType, IdType]) GetDeleteFunction() schema
 			return diag.FromErr(err)
 		}
 
+		if client.dryRun {
+			return r.dryRunDiagnostics("deleted", d.Id(), nil)
+		}
+
+		if r.beforeDelete != nil {
+			if err := r.beforeDelete(ctx, client, d, id); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
 		if r.delete != nil {
 			err := r.delete(ctx, client, partition, id)
 
@@ -316,18 +482,33 @@ func (r *resource[RequestType, ResponseType, IdType]) GetDeleteFunction() schema
 
 func (r *resource[RequestType, ResponseType, IdType]) GetDiffSupressFunction(property *resourceProperty[RequestType, ResponseType]) schema.SchemaDiffSuppressFunc {
 	if property.schema.Default == nil || property.schema.Default == "" {
-		return nil
+		return property.diffSuppress
 	}
 
 	return func(k, oldValue, newValue string, d *schema.ResourceData) bool {
+		if property.diffSuppress != nil && property.diffSuppress(k, oldValue, newValue, d) {
+			return true
+		}
+
 		return (oldValue == property.schema.Default || newValue == property.schema.Default) && (oldValue == "" || newValue == "")
 	}
 }
 
+// GetImporter reads the resource by the ID given to terraform import, importId can map it to the resource's ID first
 func (r *resource[RequestType, ResponseType, IdType]) GetImporter() *schema.ResourceImporter {
 	return &schema.ResourceImporter{
 		StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-			client := m.(*pfsenseapi.Client)
+			client := m.(*providerClient)
+
+			if r.importId != nil {
+				id, err := r.importId(ctx, client, d.Id())
+
+				if err != nil {
+					return nil, err
+				}
+
+				d.SetId(id)
+			}
 
 			if err := r.UpdateFromId(ctx, client, d); err != nil {
 				return nil, err
@@ -346,13 +527,17 @@ func (r *resource[RequestType, ResponseType, IdType]) AddResource(provider *sche
 	}
 
 	resource := &schema.Resource{
-		CreateContext: r.GetCreateFunction(),
-		ReadContext:   r.GetReadFunction(),
-		UpdateContext: r.GetUpdateFunction(),
-		DeleteContext: r.GetDeleteFunction(),
-		Importer:      r.GetImporter(),
-		Schema:        map[string]*schema.Schema{},
-		Description:   r.description,
+		CreateContext:  r.GetCreateFunction(),
+		ReadContext:    r.GetReadFunction(),
+		UpdateContext:  r.GetUpdateFunction(),
+		DeleteContext:  r.GetDeleteFunction(),
+		Importer:       r.GetImporter(),
+		CustomizeDiff:  r.customizeDiff,
+		Timeouts:       r.timeouts,
+		SchemaVersion:  r.schemaVersion,
+		StateUpgraders: r.stateUpgraders,
+		Schema:         map[string]*schema.Schema{},
+		Description:    r.description,
 	}
 
 	var idName string
@@ -374,7 +559,7 @@ func (r *resource[RequestType, ResponseType, IdType]) AddResource(provider *sche
 			panic(fmt.Sprintf("Shouldn't have get ID function set and an id property, provider error on %s", r.name))
 		}
 
-		r.getId = func(_ context.Context, _ *pfsenseapi.Client, response *ResponseType) (IdType, error) {
+		r.getId = func(_ context.Context, _ *providerClient, response *ResponseType) (IdType, error) {
 			var zeroValue IdType
 			i, err := r.properties[idName].getFromResponse(response)
 
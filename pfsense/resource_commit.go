@@ -0,0 +1,50 @@
+package pfsense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCommit() *schema.Resource {
+	return &schema.Resource{
+		Description: "Applies pending firewall and interface changes. Use this with `auto_reload = false` on the provider, " +
+			"add every resource that should be applied to `depends_on` and reference them in `triggers` so the commit is replaced whenever one of them changes. " +
+			"Unbound host overrides are always applied immediately.",
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+
+			if client.dryRun {
+				return diag.Errorf("Dry run, changes not applied")
+			}
+
+			if err := client.Firewall.Apply(ctx); err != nil {
+				return diag.Errorf("Unable to apply firewall changes: %v", err)
+			}
+
+			if err := client.Interface.Apply(ctx, false); err != nil {
+				return diag.Errorf("Unable to apply interface changes: %v", err)
+			}
+
+			d.SetId(fmt.Sprint(time.Now().UnixNano()))
+
+			return nil
+		},
+		ReadContext:   schema.NoopContext,
+		DeleteContext: schema.NoopContext,
+		Schema: map[string]*schema.Schema{
+			"triggers": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that cause the changes to be applied again when they change, e.g. the IDs of the resources this depends on.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package pfsense
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCommitTest() resourceTest {
+	return &schemaResourceTest{
+		name:     "pfsense_commit",
+		resource: resourceCommit(),
+	}
+}
+
+func Test_commitAppliesFirewallAndInterfaces(t *testing.T) {
+	client, requests := testAPIServer(t, nil)
+
+	r := resourceCommit()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"triggers": map[string]interface{}{"rule": "1"},
+	})
+
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	for _, endpoint := range []string{"POST /api/v1/firewall/apply", "POST /api/v1/interface/apply"} {
+		if !slices.Contains(*requests, endpoint) {
+			t.Errorf("Expected %s to be called", endpoint)
+		}
+	}
+
+	if d.Id() == "" {
+		t.Errorf("Expected the ID to be set")
+	}
+}
@@ -16,13 +16,13 @@ func resourceDHCPServer() *resource[pfsenseapi.DHCPServerConfigurationRequest, p
 			request.Enable = false
 			return nil
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, _ string) ([]*pfsenseapi.DHCPServerConfiguration, error) {
+		list: func(ctx context.Context, client *providerClient, _ string) ([]*pfsenseapi.DHCPServerConfiguration, error) {
 			return client.DHCP.ListServerConfigurations(ctx)
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, id string, request *pfsenseapi.DHCPServerConfigurationRequest) (*pfsenseapi.DHCPServerConfiguration, error) {
+		update: func(ctx context.Context, client *providerClient, id string, request *pfsenseapi.DHCPServerConfigurationRequest) (*pfsenseapi.DHCPServerConfiguration, error) {
 			return client.DHCP.UpdateServerConfiguration(ctx, *request)
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.DHCPServerConfigurationRequest) (*pfsenseapi.DHCPServerConfiguration, error) {
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.DHCPServerConfigurationRequest) (*pfsenseapi.DHCPServerConfiguration, error) {
 			return client.DHCP.UpdateServerConfiguration(ctx, *request)
 		},
 		properties: map[string]*resourceProperty[pfsenseapi.DHCPServerConfigurationRequest, pfsenseapi.DHCPServerConfiguration]{
@@ -2,28 +2,72 @@ package pfsense
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
+// checkStaticMappingConflicts looks through the static mappings on every DHCP server for one with the same MAC or IP
+// address, pfSense accepts these but then hands out leases inconsistently.
+func checkStaticMappingConflicts(ctx context.Context, client *providerClient, request *pfsenseapi.DHCPStaticMappingRequest) error {
+	servers, err := client.DHCP.ListServerConfigurations(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		mappings, err := client.DHCP.ListStaticMappings(ctx, server.Interface)
+
+		if err != nil {
+			return err
+		}
+
+		for _, mapping := range mappings {
+			if strings.EqualFold(mapping.Mac, request.Mac) {
+				return fmt.Errorf("MAC address %s already has a static mapping on interface %s", request.Mac, server.Interface)
+			}
+
+			if request.Ipaddr != "" && mapping.IPaddr == request.Ipaddr {
+				return fmt.Errorf("IP address %s is already mapped to %s on interface %s", request.Ipaddr, mapping.Mac, server.Interface)
+			}
+		}
+	}
+
+	return nil
+}
+
 func resourceDHCPStaticMapping() *resource[pfsenseapi.DHCPStaticMappingRequest, pfsenseapi.DHCPStaticMapping, string] {
 	return &resource[pfsenseapi.DHCPStaticMappingRequest, pfsenseapi.DHCPStaticMapping, string]{
 		name:        "pfsense_dhcp_static_mapping",
 		description: "IPv4 DHCP Static Mapping ",
-		delete: func(ctx context.Context, client *pfsenseapi.Client, interfaceName string, mac string) error {
+		delete: func(ctx context.Context, client *providerClient, interfaceName string, mac string) error {
 			return client.DHCP.DeleteStaticMapping(ctx, interfaceName, mac)
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, iface string) ([]*pfsenseapi.DHCPStaticMapping, error) {
+		list: func(ctx context.Context, client *providerClient, iface string) ([]*pfsenseapi.DHCPStaticMapping, error) {
 			return client.DHCP.ListStaticMappings(ctx, iface)
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, macAddress string, request *pfsenseapi.DHCPStaticMappingRequest) (*pfsenseapi.DHCPStaticMapping, error) {
+		update: func(ctx context.Context, client *providerClient, macAddress string, request *pfsenseapi.DHCPStaticMappingRequest) (*pfsenseapi.DHCPStaticMapping, error) {
 			return client.DHCP.UpdateStaticMapping(ctx, macAddress, *request)
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.DHCPStaticMappingRequest) (*pfsenseapi.DHCPStaticMapping, error) {
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.DHCPStaticMappingRequest) (*pfsenseapi.DHCPStaticMapping, error) {
+			if err := checkStaticMappingConflicts(ctx, client, request); err != nil {
+				return nil, err
+			}
+
 			return client.DHCP.CreateStaticMapping(ctx, *request)
 		},
+		customizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			// The ARP entry pairs the MAC with the mapping's IP so there has to be one
+			if d.Get("arp_table_static_entry").(bool) && d.Get("ip_address").(string) == "" && d.NewValueKnown("ip_address") {
+				return fmt.Errorf("arp_table_static_entry requires ip_address")
+			}
+
+			return nil
+		},
 		properties: map[string]*resourceProperty[pfsenseapi.DHCPStaticMappingRequest, pfsenseapi.DHCPStaticMapping]{
 			"interface": {
 				partition: true,
@@ -195,7 +239,7 @@ func resourceDHCPStaticMapping() *resource[pfsenseapi.DHCPStaticMappingRequest,
 				schema: &schema.Schema{
 					Type:        schema.TypeBool,
 					Optional:    true,
-					Description: "Create a static ARP entry for this static mapping.",
+					Description: "Create a static ARP entry for this static mapping, pfSense adds and removes it along with the mapping. Requires `ip_address`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.DHCPStaticMappingRequest) error {
 					req.ArpTableStaticEntry = d.Get(name).(bool)
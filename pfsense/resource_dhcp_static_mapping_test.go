@@ -1,6 +1,15 @@
 package pfsense
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
@@ -9,3 +18,103 @@ func resourceDhcpStaticMappingTest() resourceTest {
 		resource: resourceDHCPStaticMapping(),
 	}
 }
+
+func Test_dhcpStaticMappingDuplicates(t *testing.T) {
+	responses := map[string]string{
+		"GET /api/v1/services/dhcpd":                 `[{"interface": "lan"}, {"interface": "opt1"}]`,
+		"GET /api/v1/services/dhcpd/static_mapping":  `[{"id": 0, "mac": "aa:bb:cc:dd:ee:ff", "ipaddr": "192.168.1.10"}]`,
+		"POST /api/v1/services/dhcpd/static_mapping": `{"id": 1, "mac": "aa:bb:cc:dd:ee:01", "ipaddr": "192.168.1.11"}`,
+	}
+
+	tests := map[string]struct {
+		request pfsenseapi.DHCPStaticMappingRequest
+		err     string
+	}{
+		"unique":        {pfsenseapi.DHCPStaticMappingRequest{Interface: "opt1", Mac: "aa:bb:cc:dd:ee:01", Ipaddr: "192.168.1.11"}, ""},
+		"duplicate mac": {pfsenseapi.DHCPStaticMappingRequest{Interface: "opt1", Mac: "AA:BB:CC:DD:EE:FF", Ipaddr: "192.168.1.11"}, "MAC address AA:BB:CC:DD:EE:FF already has a static mapping on interface lan"},
+		"duplicate ip":  {pfsenseapi.DHCPStaticMappingRequest{Interface: "lan", Mac: "aa:bb:cc:dd:ee:01", Ipaddr: "192.168.1.10"}, "IP address 192.168.1.10 is already mapped to aa:bb:cc:dd:ee:ff on interface lan"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, _ := testAPIServer(t, responses)
+
+			_, err := resourceDHCPStaticMapping().create(context.Background(), client, &test.request)
+
+			if test.err == "" && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected error %q but got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func Test_dhcpStaticMappingArpEntry(t *testing.T) {
+	for _, config := range []map[string]interface{}{
+		{"interface": "lan", "mac": "aa:bb:cc:dd:ee:01", "ip_address": "192.168.1.11", "arp_table_static_entry": true},
+		{"interface": "lan", "mac": "aa:bb:cc:dd:ee:01"},
+	} {
+		if err := planResource(resourceDHCPStaticMapping(), config); err != nil {
+			t.Errorf("Expected %v to be valid but got %v", config, err)
+		}
+	}
+
+	err := planResource(resourceDHCPStaticMapping(), map[string]interface{}{"interface": "lan", "mac": "aa:bb:cc:dd:ee:01", "arp_table_static_entry": true})
+
+	if err == nil || !strings.Contains(err.Error(), "arp_table_static_entry requires ip_address") {
+		t.Errorf("Expected an error without ip_address but got %v", err)
+	}
+}
+
+func Test_dhcpStaticMappingArpEntryCreateDelete(t *testing.T) {
+	var created pfsenseapi.DHCPStaticMappingRequest
+	deleted := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := "null"
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/services/dhcpd/static_mapping":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &created)
+			data = `{"id": 0, "mac": "aa:bb:cc:dd:ee:01", "ipaddr": "192.168.1.11", "arp_table_static_entry": ""}`
+		case "GET /api/v1/services/dhcpd/static_mapping":
+			data = `[{"id": 0, "mac": "aa:bb:cc:dd:ee:01", "ipaddr": "192.168.1.11", "arp_table_static_entry": ""}]`
+		case "DELETE /api/v1/services/dhcpd/static_mapping":
+			deleted = true
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + data + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false)
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceDHCPStaticMapping().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_dhcp_static_mapping"]
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"interface":              "lan",
+		"mac":                    "aa:bb:cc:dd:ee:01",
+		"ip_address":             "192.168.1.11",
+		"arp_table_static_entry": true,
+	})
+
+	if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unable to create static mapping: %v", diags)
+	}
+
+	if !created.ArpTableStaticEntry || created.Ipaddr != "192.168.1.11" {
+		t.Errorf("Expected the ARP entry to be requested with the mapping but got %+v", created)
+	}
+
+	if !d.Get("arp_table_static_entry").(bool) {
+		t.Errorf("Expected arp_table_static_entry to be read back")
+	}
+
+	if diags := res.DeleteContext(context.Background(), d, client); diags.HasError() || !deleted {
+		t.Errorf("Expected the mapping, and with it the ARP entry, to be deleted but got %v", diags)
+	}
+}
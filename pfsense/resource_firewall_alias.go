@@ -2,7 +2,14 @@ package pfsense
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
@@ -10,22 +17,506 @@ import (
 
 const addressSplitter = " "
 const detailSplitter = "||"
+const aliasTypeAuto = "auto"
+
+const (
+	allowEmptyWarn  = "warn"
+	allowEmptyError = "error"
+	allowEmptyAllow = "allow"
+)
+
+const (
+	addressFamilyInet  = "inet"
+	addressFamilyInet6 = "inet6"
+	addressFamilyBoth  = "both"
+)
+
+const emptyAliasMessage = "Alias %s has no targets, pfSense treats rules using an empty alias inconsistently"
+
+// aliasClient is the part of the API client the alias resource uses, it's an interface so it can be replaced in tests
+// or wrapped.
+type aliasClient interface {
+	ListAliases(ctx context.Context) ([]*pfsenseapi.FirewallAlias, error)
+	CreateAlias(ctx context.Context, request pfsenseapi.FirewallAliasRequest, apply bool) (*pfsenseapi.FirewallAlias, error)
+	UpdateAlias(ctx context.Context, name string, request pfsenseapi.FirewallAliasRequest, apply bool) (*pfsenseapi.FirewallAlias, error)
+	DeleteAlias(ctx context.Context, name string, apply bool) error
+}
+
+const largeAliasSize = 1000
+
+// clearAliasEntries sends an alias without targets with empty entries, the API keeps the current entries when address
+// is null so they would never be removed
+func clearAliasEntries(request *pfsenseapi.FirewallAliasRequest) {
+	if len(request.Address) == 0 {
+		request.Address = []string{}
+		request.Detail = []string{}
+	}
+}
+
+// aliasWriter picks the client to write the alias with, large aliases get a longer request timeout. The API can append
+// entries to an alias but it doesn't keep their order, so the alias is still written in one request.
+func aliasWriter(client *providerClient, request *pfsenseapi.FirewallAliasRequest) aliasClient {
+	if len(request.Address) >= largeAliasSize && client.largeAliases != nil {
+		return client.largeAliases
+	}
+
+	return client.aliases
+}
+
+func aliasAddresses(targets []interface{}) []string {
+	addresses := make([]string, len(targets))
+
+	for i, target := range targets {
+		if targetMap, ok := target.(map[string]interface{}); ok {
+			addresses[i], _ = targetMap["address"].(string)
+		}
+	}
+
+	return addresses
+}
+
+func isPortOrRange(value string) bool {
+	for _, part := range strings.Split(value, ":") {
+		port, err := strconv.Atoi(part)
+
+		if err != nil || port < 1 || port > 65535 {
+			return false
+		}
+	}
+
+	return !strings.HasPrefix(value, ":") && strings.Count(value, ":") <= 1
+}
+
+// inferAliasType works out the alias type from the addresses, each address must be of the same kind.
+func inferAliasType(addresses []string) (string, error) {
+	if len(addresses) == 0 {
+		return "", fmt.Errorf("Unable to infer alias type without any targets")
+	}
+
+	var aliasType string
+
+	for _, address := range addresses {
+		var addressType string
+
+		if _, _, err := net.ParseCIDR(address); err == nil {
+			addressType = "network"
+		} else if net.ParseIP(address) != nil {
+			addressType = "host"
+		} else if isPortOrRange(address) {
+			addressType = "port"
+		} else {
+			return "", fmt.Errorf("Unable to infer alias type from address '%s', set type explicitly", address)
+		}
+
+		if aliasType != "" && aliasType != addressType {
+			return "", fmt.Errorf("Unable to infer alias type, targets contain both %s and %s addresses", aliasType, addressType)
+		}
+
+		aliasType = addressType
+	}
+
+	return aliasType, nil
+}
+
+// addressFamily returns inet or inet6 for an address, CIDR or range and an empty string for anything else
+func addressFamily(address string) string {
+	if prefix, err := netip.ParsePrefix(address); err == nil {
+		address = prefix.Addr().String()
+	} else if first, _, found := strings.Cut(address, "-"); found {
+		address = first
+	}
+
+	addr, err := netip.ParseAddr(address)
+
+	if err != nil {
+		return ""
+	} else if addr.Is4() {
+		return addressFamilyInet
+	}
+
+	return addressFamilyInet6
+}
+
+// checkAddressFamily makes sure every address, CIDR and range is of the family, other entries such as hostnames and
+// nested aliases can't be checked without resolving them
+func checkAddressFamily(addresses []string, family string) error {
+	if family == addressFamilyBoth {
+		return nil
+	}
+
+	for _, address := range addresses {
+		if actual := addressFamily(address); actual != "" && actual != family {
+			return fmt.Errorf("Target %s is %s but address_family is %s", address, actual, family)
+		}
+	}
+
+	return nil
+}
+
+// aliasReferences lists the rules and aliases that refer to the alias by name
+func aliasReferences(ctx context.Context, client *providerClient, name string) ([]string, error) {
+	var references []string
+
+	rules, err := client.rules.get(ctx, client.Firewall.ListRules)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		for _, target := range []*pfsenseapi.FirewallTarget{rule.Source, rule.Destination} {
+			if target != nil && (strings.TrimPrefix(target.TargetString(), "!") == name || target.Port == name) {
+				references = append(references, fmt.Sprintf("rule %d (%s)", rule.Tracker, rule.Descr))
+				break
+			}
+		}
+	}
+
+	aliases, err := client.aliases.ListAliases(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		if alias.Name != name && slices.Contains(splitIntoArray(alias.Address, addressSplitter), name) {
+			references = append(references, fmt.Sprintf("alias %s", alias.Name))
+		}
+	}
+
+	return references, nil
+}
+
+var aliasNamePattern = regexValidator(`^\w+$`)
+
+// aliasNameMaxLength is the longest alias name pfSense accepts
+const aliasNameMaxLength = 31
+
+// validateAliasName checks the name the way pfSense does, it's at most 31 alpha-numeric or underscore characters and
+// isn't only digits or only underscores
+func validateAliasName(i interface{}, k string) ([]string, []error) {
+	name, ok := i.(string)
+
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+
+	if !aliasNamePattern.MatchString(name) {
+		return nil, []error{fmt.Errorf("%s %q can only contain alpha-numeric and underscore characters", k, name)}
+	}
+
+	if len(name) > aliasNameMaxLength {
+		return nil, []error{fmt.Errorf("%s %q is %d characters long, pfSense allows at most %d", k, name, len(name), aliasNameMaxLength)}
+	}
+
+	if strings.Trim(name, "0123456789") == "" || strings.Trim(name, "_") == "" {
+		return nil, []error{fmt.Errorf("%s %q can't be only digits or only underscores", k, name)}
+	}
+
+	return nil, nil
+}
+
+// aliasNameTaken fails if an alias other than current has the name, pfSense compares alias names ignoring case and
+// would otherwise fail the write part way through an apply, e.g. when names generated for for_each collide
+func aliasNameTaken(aliases []*pfsenseapi.FirewallAlias, name string, current string) error {
+	for _, alias := range aliases {
+		if alias.Name != current && strings.EqualFold(alias.Name, name) {
+			return fmt.Errorf("Alias name %s is already taken by %s, an alias this resource doesn't manage, import it or choose a different name", name, alias.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkAliasNameAvailable makes sure the name isn't taken when the alias is written, current is the alias' name before
+// a rename
+func checkAliasNameAvailable(ctx context.Context, client *providerClient, name string, current string) error {
+	aliases, err := client.aliases.ListAliases(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return aliasNameTaken(aliases, name, current)
+}
+
+// checkPlannedAliasName makes sure the name of a new or renamed alias isn't taken while planning, so colliding names fail
+// the plan rather than the apply after other aliases have been written. The alias list is cached for the rest of the plan.
+func checkPlannedAliasName(ctx context.Context, client *providerClient, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("name") || (d.Id() != "" && !d.HasChange("name")) {
+		return nil
+	}
+
+	aliases, err := client.aliasCache.get(ctx, client.aliases.ListAliases)
+
+	if err != nil {
+		return err
+	}
+
+	return aliasNameTaken(aliases, d.Get("name").(string), d.Id())
+}
+
+// nestedAliasNames returns the entries that refer to other aliases, they're the entries that could be an alias name and
+// aren't a port. Hostnames have to be fully qualified so they aren't mistaken for aliases.
+func nestedAliasNames(addresses []string) []string {
+	var names []string
+
+	for _, address := range addresses {
+		if aliasNamePattern.MatchString(address) && !isPortOrRange(address) {
+			names = append(names, address)
+		}
+	}
+
+	return names
+}
+
+// aliasWalk follows nested aliases depth first. Each alias is walked once however many aliases refer to it, so large
+// nested aliases are walked in linear time.
+type aliasWalk struct {
+	aliases map[string]*pfsenseapi.FirewallAlias
+	// alias is called with each alias before its entries are walked
+	alias func(alias *pfsenseapi.FirewallAlias) error
+	// entry is called with each entry that isn't an alias along with the name of the alias it's in
+	entry func(parent string, entry string) error
+	// loop is called when an alias leads back to one that's being walked, the path ends with that alias. Loops are an
+	// error when it isn't set.
+	loop    func(path []string) error
+	walking map[string]struct{}
+	walked  map[string]struct{}
+}
+
+func newAliasWalk(aliases []*pfsenseapi.FirewallAlias) *aliasWalk {
+	w := &aliasWalk{
+		aliases: make(map[string]*pfsenseapi.FirewallAlias, len(aliases)),
+		walking: map[string]struct{}{},
+		walked:  map[string]struct{}{},
+	}
+
+	for _, alias := range aliases {
+		w.aliases[alias.Name] = alias
+	}
+
+	return w
+}
+
+// walk walks the entries, path is the aliases that lead to them
+func (w *aliasWalk) walk(path []string, entries []string) error {
+	for _, entry := range entries {
+		alias, ok := w.aliases[entry]
+
+		if !ok {
+			if w.entry == nil {
+				continue
+			}
+
+			parent := ""
+
+			if len(path) > 0 {
+				parent = path[len(path)-1]
+			}
+
+			if err := w.entry(parent, entry); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, loop := w.walking[entry]; loop {
+			loopPath := append(slices.Clone(path), entry)
+
+			if w.loop == nil {
+				return fmt.Errorf("Alias %s refers to itself through %s", entry, strings.Join(loopPath, " -> "))
+			}
+
+			if err := w.loop(loopPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, walked := w.walked[entry]; walked {
+			continue
+		}
+
+		if w.alias != nil {
+			if err := w.alias(alias); err != nil {
+				return err
+			}
+		}
+
+		w.walking[entry] = struct{}{}
+		err := w.walk(append(path, entry), splitIntoArray(alias.Address, addressSplitter))
+		delete(w.walking, entry)
+
+		if err != nil {
+			return err
+		}
+
+		w.walked[entry] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkNestedAliases makes sure the aliases the request refers to exist and that none of them lead back to the alias
+// being written, pfSense would otherwise loop resolving it.
+func checkNestedAliases(ctx context.Context, client *providerClient, request *pfsenseapi.FirewallAliasRequest) error {
+	names := nestedAliasNames(request.Address)
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	aliases, err := client.aliases.ListAliases(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	w := newAliasWalk(aliases)
+	w.aliases[request.Name] = &pfsenseapi.FirewallAlias{Name: request.Name, Type: request.Type, Address: strings.Join(request.Address, addressSplitter)}
+
+	// Entries of other aliases are already on pfSense, only the names in the request have to be aliases
+	w.entry = func(parent string, entry string) error {
+		if parent == request.Name && slices.Contains(names, entry) {
+			return fmt.Errorf("Alias %s refers to %s which isn't an alias, hostnames have to be fully qualified", request.Name, entry)
+		}
+
+		return nil
+	}
+
+	// Loops between other aliases are already on pfSense, only the ones through this alias are its doing
+	w.loop = func(path []string) error {
+		if path[len(path)-1] == request.Name {
+			return fmt.Errorf("Alias %s would refer to itself through %s", request.Name, strings.Join(path, " -> "))
+		}
+
+		return nil
+	}
+
+	return w.walk(nil, []string{request.Name})
+}
 
 func resourceFirewallAlias() *resource[pfsenseapi.FirewallAliasRequest, pfsenseapi.FirewallAlias, string] {
 	return &resource[pfsenseapi.FirewallAliasRequest, pfsenseapi.FirewallAlias, string]{
 		name:        "pfsense_firewall_alias",
 		description: "Firewall Alias",
-		delete: func(ctx context.Context, client *pfsenseapi.Client, _ string, name string) error {
-			return client.Firewall.DeleteAlias(ctx, name, true)
+		delete: func(ctx context.Context, client *providerClient, _ string, name string) error {
+			defer client.aliasLocks.lock(name)()
+			defer client.lockReload(client.autoReload)()
+			defer client.aliasCache.invalidate()
+
+			return client.aliases.DeleteAlias(ctx, name, client.autoReload)
+		},
+		list: func(ctx context.Context, client *providerClient, _ string) ([]*pfsenseapi.FirewallAlias, error) {
+			return client.aliases.ListAliases(ctx)
+		},
+		update: func(ctx context.Context, client *providerClient, name string, request *pfsenseapi.FirewallAliasRequest) (*pfsenseapi.FirewallAlias, error) {
+			// A rename takes the new name as well so nothing else can create it in the meantime
+			defer client.aliasLocks.lock(name, request.Name)()
+			defer client.lockReload(client.autoReload)()
+			defer client.aliasCache.invalidate()
+
+			if request.Name != name {
+				if err := checkAliasNameAvailable(ctx, client, request.Name, name); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := checkNestedAliases(ctx, client, request); err != nil {
+				return nil, err
+			}
+
+			clearAliasEntries(request)
+
+			return aliasWriter(client, request).UpdateAlias(ctx, name, *request, client.autoReload)
+		},
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.FirewallAliasRequest) (*pfsenseapi.FirewallAlias, error) {
+			defer client.aliasLocks.lock(request.Name)()
+			defer client.lockReload(client.autoReload)()
+			defer client.aliasCache.invalidate()
+
+			if err := checkAliasNameAvailable(ctx, client, request.Name, ""); err != nil {
+				return nil, err
+			}
+
+			if err := checkNestedAliases(ctx, client, request); err != nil {
+				return nil, err
+			}
+
+			clearAliasEntries(request)
+
+			return aliasWriter(client, request).CreateAlias(ctx, *request, client.autoReload)
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, _ string) ([]*pfsenseapi.FirewallAlias, error) {
-			return client.Firewall.ListAliases(ctx)
+		beforeDelete: func(ctx context.Context, client *providerClient, d *schema.ResourceData, name string) error {
+			if d.Get("force_destroy").(bool) {
+				return nil
+			}
+
+			references, err := aliasReferences(ctx, client, name)
+
+			if err != nil {
+				return err
+			}
+
+			if len(references) > 0 {
+				return fmt.Errorf("Alias %s is still referenced by %s, remove the references or set force_destroy", name, strings.Join(references, ", "))
+			}
+
+			return nil
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, name string, request *pfsenseapi.FirewallAliasRequest) (*pfsenseapi.FirewallAlias, error) {
-			return client.Firewall.UpdateAlias(ctx, name, *request, true)
+		warnings: func(d *schema.ResourceData) diag.Diagnostics {
+			if d.Get("allow_empty").(string) == allowEmptyWarn && len(d.Get("target").([]interface{})) == 0 {
+				return diag.Diagnostics{{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf(emptyAliasMessage, d.Get("name")),
+					Detail:   "Add a target or set allow_empty to \"allow\" to silence this warning.",
+				}}
+			}
+
+			return nil
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.FirewallAliasRequest) (*pfsenseapi.FirewallAlias, error) {
-			return client.Firewall.CreateAlias(ctx, *request, true)
+		customizeDiff: func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			if client, ok := m.(*providerClient); ok {
+				if err := checkPlannedAliasName(ctx, client, d); err != nil {
+					return err
+				}
+			}
+
+			if d.Get("allow_empty").(string) == allowEmptyError && d.NewValueKnown("target") && len(d.Get("target").([]interface{})) == 0 {
+				return fmt.Errorf(emptyAliasMessage+", add a target or change allow_empty", d.Get("name"))
+			}
+
+			if err := checkAddressFamily(aliasAddresses(d.Get("target").([]interface{})), d.Get("address_family").(string)); err != nil {
+				return err
+			}
+
+			aliasType := d.Get("type").(string)
+
+			if !d.NewValueKnown("type") {
+				return d.SetNewComputed("resolved_type")
+			}
+
+			if aliasType != aliasTypeAuto {
+				return d.SetNew("resolved_type", aliasType)
+			}
+
+			addresses := aliasAddresses(d.Get("target").([]interface{}))
+
+			// Addresses that are not known until apply are read as empty strings
+			if slices.Contains(addresses, "") {
+				return d.SetNewComputed("resolved_type")
+			}
+
+			resolvedType, err := inferAliasType(addresses)
+
+			if err != nil {
+				return err
+			}
+
+			return d.SetNew("resolved_type", resolvedType)
 		},
 		properties: map[string]*resourceProperty[pfsenseapi.FirewallAliasRequest, pfsenseapi.FirewallAlias]{
 			"name": {
@@ -33,8 +524,8 @@ func resourceFirewallAlias() *resource[pfsenseapi.FirewallAliasRequest, pfsensea
 				schema: &schema.Schema{
 					Type:         schema.TypeString,
 					Required:     true,
-					ValidateFunc: validation.StringMatch(regexValidator(`^\w+$`), "Only alpha-numeric and underscore characters are allowed"),
-					Description:  "Name of the new alias. Only alpha-numeric and underscore characters are allowed",
+					ValidateFunc: validateAliasName,
+					Description:  "Name of the new alias. Only alpha-numeric and underscore characters are allowed, up to 31 of them, and it can't be only digits or only underscores.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallAliasRequest) error {
 					req.Name = d.Get(name).(string)
@@ -44,6 +535,32 @@ func resourceFirewallAlias() *resource[pfsenseapi.FirewallAliasRequest, pfsensea
 					return req.Name, nil
 				},
 			},
+			"allow_empty": {
+				schema: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      allowEmptyWarn,
+					Description:  "What to do when the alias has no targets, `warn`, `error` or `allow`.",
+					ValidateFunc: validation.StringInSlice([]string{allowEmptyWarn, allowEmptyError, allowEmptyAllow}, false),
+				},
+			},
+			"address_family": {
+				schema: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      addressFamilyBoth,
+					Description:  "Address family the targets must be in, `inet`, `inet6` or `both`. Addresses, CIDRs and ranges are checked at plan time, hostnames and nested aliases aren't.",
+					ValidateFunc: validation.StringInSlice([]string{addressFamilyInet, addressFamilyInet6, addressFamilyBoth}, false),
+				},
+			},
+			"force_destroy": {
+				schema: &schema.Schema{
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     false,
+					Description: "Delete the alias even when firewall rules or other aliases still refer to it. NAT rules aren't checked.",
+				},
+			},
 			"description": {
 				schema: &schema.Schema{
 					Type:        schema.TypeString,
@@ -62,27 +579,57 @@ func resourceFirewallAlias() *resource[pfsenseapi.FirewallAliasRequest, pfsensea
 				schema: &schema.Schema{
 					Type:         schema.TypeString,
 					Required:     true,
-					ValidateFunc: validation.StringInSlice([]string{"host", "network", "port"}, false),
-					Description:  "Type of alias.",
+					ValidateFunc: validation.StringInSlice([]string{"host", "network", "port", aliasTypeAuto}, false),
+					Description:  "Type of alias. When set to `auto` the type is inferred from the targets, all targets must then be IP addresses (`host`), CIDRs (`network`) or ports (`port`).",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallAliasRequest) error {
 					req.Type = d.Get(name).(string)
+
+					if req.Type == aliasTypeAuto {
+						resolvedType, err := inferAliasType(aliasAddresses(d.Get("target").([]interface{})))
+
+						if err != nil {
+							return err
+						}
+
+						req.Type = resolvedType
+					}
+
 					return nil
 				},
 				getFromResponse: func(req *pfsenseapi.FirewallAlias) (interface{}, error) {
 					return req.Type, nil
 				},
+				diffSuppress: func(_, oldValue, newValue string, d *schema.ResourceData) bool {
+					if newValue != aliasTypeAuto {
+						return false
+					}
+
+					resolvedType, err := inferAliasType(aliasAddresses(d.Get("target").([]interface{})))
+
+					return err == nil && resolvedType == oldValue
+				},
+			},
+			"resolved_type": {
+				schema: &schema.Schema{
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Type of the alias in pfSense, this is the inferred type when `type` is set to `auto`.",
+				},
+				getFromResponse: func(req *pfsenseapi.FirewallAlias) (interface{}, error) {
+					return req.Type, nil
+				},
 			},
 			"target": {
 				schema: &schema.Schema{
 					Type:     schema.TypeList,
-					Required: true,
+					Optional: true,
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"address": {
 								Type:         schema.TypeString,
 								Required:     true,
-								Description:  "Host, network or port values to add to the alias.",
+								Description:  "Host, network or port values to add to the alias, or the name of another alias to include. Aliases that are referred to must exist, hostnames have to be fully qualified so they aren't taken for alias names.",
 								ValidateFunc: validation.StringDoesNotContainAny(" "),
 							},
 							"description": {
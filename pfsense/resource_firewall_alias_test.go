@@ -1,6 +1,20 @@
 package pfsense
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
@@ -9,3 +23,894 @@ func resourceFirewallAliasTest() resourceTest {
 		resource: resourceFirewallAlias(),
 	}
 }
+
+func Test_inferAliasType(t *testing.T) {
+	tests := map[string]struct {
+		addresses []string
+		expected  string
+		err       bool
+	}{
+		"hosts":          {addresses: []string{"192.168.1.1", "fd00::1"}, expected: "host"},
+		"networks":       {addresses: []string{"192.168.1.0/24", "10.0.0.0/8", "fd00::/64"}, expected: "network"},
+		"ports":          {addresses: []string{"80", "443", "8000:8080"}, expected: "port"},
+		"hosts and cidr": {addresses: []string{"192.168.1.1", "10.0.0.0/8"}, err: true},
+		"ports and host": {addresses: []string{"443", "192.168.1.1"}, err: true},
+		"fqdn":           {addresses: []string{"example.com"}, err: true},
+		"invalid port":   {addresses: []string{"70000"}, err: true},
+		"empty":          {addresses: []string{}, err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := inferAliasType(test.addresses)
+
+			if test.err {
+				if err == nil {
+					t.Errorf("Expected an error for %v but got type %s", test.addresses, result)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error for %v: %v", test.addresses, err)
+			} else if result != test.expected {
+				t.Errorf("Expected type %s for %v but got %s", test.expected, test.addresses, result)
+			}
+		})
+	}
+}
+
+func Test_firewallAliasDeleteGuard(t *testing.T) {
+	responses := map[string]string{
+		"GET /api/v1/firewall/rule": `[
+			{"tracker": "1", "descr": "Web", "destination": {"address": "web_servers", "port": "443"}},
+			{"tracker": "2", "descr": "Other", "destination": {"address": "10.0.0.1"}}
+		]`,
+		"GET /api/v1/firewall/alias": `[
+			{"name": "web_servers", "type": "host", "address": "10.0.0.10"},
+			{"name": "all_servers", "type": "host", "address": "web_servers 10.0.0.20"}
+		]`,
+	}
+
+	tests := map[string]struct {
+		forceDestroy bool
+		deleted      bool
+	}{
+		"referenced":    {false, false},
+		"force_destroy": {true, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, requests := testAPIServer(t, responses)
+
+			provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+			resourceFirewallAlias().AddResource(provider)
+			res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+			d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+				"name":          "web_servers",
+				"type":          "host",
+				"force_destroy": test.forceDestroy,
+			})
+			d.SetId("web_servers")
+
+			diags := res.DeleteContext(context.Background(), d, client)
+			deleted := slices.Contains(*requests, "DELETE /api/v1/firewall/alias")
+
+			if deleted != test.deleted {
+				t.Errorf("Expected deleted to be %v but was %v (%v)", test.deleted, deleted, diags)
+			}
+
+			if !test.deleted && (!diags.HasError() || !strings.Contains(diags[0].Summary, "rule 1 (Web), alias all_servers")) {
+				t.Errorf("Expected an error listing the references but got %v", diags)
+			}
+		})
+	}
+}
+
+func Test_firewallAliasAddressFamily(t *testing.T) {
+	targets := func(addresses ...string) []interface{} {
+		result := []interface{}{}
+
+		for _, address := range addresses {
+			result = append(result, map[string]interface{}{"address": address})
+		}
+
+		return result
+	}
+
+	tests := map[string]struct {
+		aliasType string
+		family    string
+		targets   []interface{}
+		invalid   bool
+	}{
+		"ipv6 hosts":           {"host", "inet6", targets("fd00::1", "2001:db8::10"), false},
+		"ipv6 networks":        {"network", "inet6", targets("fd00::/64", "2001:db8::/32"), false},
+		"ipv6 range":           {"host", "inet6", targets("fd00::1-fd00::ff"), false},
+		"mixed both":           {"host", "", targets("10.0.0.1", "fd00::1"), false},
+		"mixed ipv4":           {"host", "inet", targets("10.0.0.1", "fd00::1"), true},
+		"mixed ipv6":           {"network", "inet6", targets("fd00::/64", "10.0.0.0/8"), true},
+		"ipv4 range in ipv6":   {"host", "inet6", targets("10.0.0.1-10.0.0.9"), true},
+		"hostnames unchecked":  {"host", "inet", targets("10.0.0.1", "host.example.com"), false},
+		"ports unchecked":      {"port", "inet6", targets("443"), false},
+		"auto infers ipv6":     {"auto", "inet6", targets("fd00::1"), false},
+		"auto rejects mixture": {"auto", "inet", targets("fd00::1"), true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"name": "hosts", "type": test.aliasType, "target": test.targets}
+
+			if test.family != "" {
+				config["address_family"] = test.family
+			}
+
+			if err := planResource(resourceFirewallAlias(), config); test.invalid != (err != nil) {
+				t.Errorf("Expected invalid to be %v but got %v", test.invalid, err)
+			}
+		})
+	}
+}
+
+func Test_firewallAliasAllowEmpty(t *testing.T) {
+	tests := map[string]struct {
+		config  map[string]interface{}
+		invalid bool
+		warning bool
+	}{
+		"empty warns by default": {
+			config:  map[string]interface{}{"name": "empty", "type": "host"},
+			warning: true,
+		},
+		"empty errors": {
+			config:  map[string]interface{}{"name": "empty", "type": "host", "allow_empty": "error"},
+			invalid: true,
+		},
+		"empty allowed": {
+			config: map[string]interface{}{"name": "empty", "type": "host", "allow_empty": "allow"},
+		},
+		"not empty": {
+			config: map[string]interface{}{"name": "hosts", "type": "host", "allow_empty": "error", "target": []interface{}{map[string]interface{}{"address": "10.0.0.1"}}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := planResource(resourceFirewallAlias(), test.config)
+
+			if test.invalid != (err != nil) {
+				t.Fatalf("Expected invalid to be %v but got %v", test.invalid, err)
+			}
+
+			if test.invalid {
+				return
+			}
+
+			client, _ := testAPIServer(t, map[string]string{
+				"POST /api/v1/firewall/alias": `{"name": "` + test.config["name"].(string) + `", "type": "host"}`,
+			})
+
+			provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+			resourceFirewallAlias().AddResource(provider)
+			res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+			diags := res.CreateContext(context.Background(), schema.TestResourceDataRaw(t, res.Schema, test.config), client)
+
+			if diags.HasError() {
+				t.Fatalf("Unexpected error %v", diags)
+			}
+
+			if test.warning != (len(diags) == 1) {
+				t.Errorf("Expected warning to be %v but got %v", test.warning, diags)
+			}
+		})
+	}
+}
+
+type mockAliasClient struct {
+	aliases map[string]*pfsenseapi.FirewallAlias
+	calls   []string
+	// maxEntries fails writes with more entries the way a request timeout would, zero is unlimited
+	maxEntries int
+}
+
+func (m *mockAliasClient) checkSize(request pfsenseapi.FirewallAliasRequest) error {
+	if m.maxEntries > 0 && len(request.Address) > m.maxEntries {
+		return fmt.Errorf("Client.Timeout exceeded while writing %d entries", len(request.Address))
+	}
+
+	return nil
+}
+
+func (m *mockAliasClient) toAlias(request pfsenseapi.FirewallAliasRequest) *pfsenseapi.FirewallAlias {
+	return &pfsenseapi.FirewallAlias{
+		Name:    request.Name,
+		Type:    request.Type,
+		Address: strings.Join(request.Address, addressSplitter),
+		Descr:   request.Descr,
+		Detail:  strings.Join(request.Detail, detailSplitter),
+	}
+}
+
+func (m *mockAliasClient) ListAliases(_ context.Context) ([]*pfsenseapi.FirewallAlias, error) {
+	m.calls = append(m.calls, "list")
+	aliases := []*pfsenseapi.FirewallAlias{}
+
+	for _, alias := range m.aliases {
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+func (m *mockAliasClient) CreateAlias(_ context.Context, request pfsenseapi.FirewallAliasRequest, _ bool) (*pfsenseapi.FirewallAlias, error) {
+	m.calls = append(m.calls, "create")
+
+	if err := m.checkSize(request); err != nil {
+		return nil, err
+	}
+
+	if _, exists := m.aliases[request.Name]; exists {
+		return nil, fmt.Errorf("alias %s already exists", request.Name)
+	}
+
+	m.aliases[request.Name] = m.toAlias(request)
+
+	return m.aliases[request.Name], nil
+}
+
+func (m *mockAliasClient) UpdateAlias(_ context.Context, name string, request pfsenseapi.FirewallAliasRequest, _ bool) (*pfsenseapi.FirewallAlias, error) {
+	m.calls = append(m.calls, "update")
+
+	if err := m.checkSize(request); err != nil {
+		return nil, err
+	}
+
+	if _, exists := m.aliases[name]; !exists {
+		return nil, fmt.Errorf("alias %s doesn't exist", name)
+	}
+
+	delete(m.aliases, name)
+	m.aliases[request.Name] = m.toAlias(request)
+
+	return m.aliases[request.Name], nil
+}
+
+func (m *mockAliasClient) DeleteAlias(_ context.Context, name string, _ bool) error {
+	m.calls = append(m.calls, "delete")
+
+	if _, exists := m.aliases[name]; !exists {
+		return fmt.Errorf("alias %s doesn't exist", name)
+	}
+
+	delete(m.aliases, name)
+
+	return nil
+}
+
+func Test_firewallAliasRemoveAllTargets(t *testing.T) {
+	var written map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := `[{"name": "web_servers", "type": "host", "address": "10.0.0.10 10.0.0.11", "detail": "||"}]`
+
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&written); err != nil {
+				t.Errorf("Unable to parse the request: %v", err)
+			}
+
+			data = `{"name": "web_servers", "type": "host", "address": "", "detail": ""}`
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + data + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false)
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	state := &terraform.InstanceState{ID: "web_servers", Attributes: map[string]string{
+		"name": "web_servers", "type": "host", "target.#": "2", "target.0.address": "10.0.0.10", "target.1.address": "10.0.0.11",
+	}}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "web_servers", "type": "host", "allow_empty": "allow"})
+
+	diff, err := res.Diff(context.Background(), state, config, client)
+
+	if err != nil {
+		t.Fatalf("Unable to plan removing the targets: %v", err)
+	}
+
+	state, diags := res.Apply(context.Background(), state, diff, client)
+
+	if diags.HasError() {
+		t.Fatalf("Unable to remove the targets: %v", diags)
+	}
+
+	for _, name := range []string{"address", "detail"} {
+		if entries, ok := written[name].([]interface{}); !ok || len(entries) != 0 {
+			t.Errorf("Expected %s to be written as an empty list but got %#v", name, written[name])
+		}
+	}
+
+	if state.Attributes["target.#"] != "0" {
+		t.Errorf("Expected no targets in the state but got %v", state.Attributes)
+	}
+}
+
+func Test_firewallAliasLifecycle(t *testing.T) {
+	mock := &mockAliasClient{aliases: map[string]*pfsenseapi.FirewallAlias{}}
+	client := &providerClient{aliases: mock}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"name":          "web_servers",
+		"type":          "auto",
+		"force_destroy": true,
+		"target": []interface{}{
+			map[string]interface{}{"address": "10.0.0.10", "description": "web1"},
+			map[string]interface{}{"address": "10.0.0.11", "description": "web2"},
+		},
+	})
+
+	if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unable to create alias: %v", diags)
+	}
+
+	expected := &pfsenseapi.FirewallAlias{Name: "web_servers", Type: "host", Address: "10.0.0.10 10.0.0.11", Detail: "web1||web2"}
+
+	if !reflect.DeepEqual(mock.aliases["web_servers"], expected) {
+		t.Errorf("Expected %+v to be created but got %+v", expected, mock.aliases["web_servers"])
+	}
+
+	mock.aliases["web_servers"].Address = "10.0.0.10"
+	mock.aliases["web_servers"].Detail = "web1"
+
+	if diags := res.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unable to read alias: %v", diags)
+	}
+
+	if targets := d.Get("target").([]interface{}); len(targets) != 1 || d.Get("resolved_type") != "host" {
+		t.Errorf("Expected the change made outside terraform to be read but got %v", targets)
+	}
+
+	if diags := res.UpdateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unable to update alias: %v", diags)
+	}
+
+	if diags := res.DeleteContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unable to delete alias: %v", diags)
+	}
+
+	if len(mock.aliases) != 0 {
+		t.Errorf("Expected the alias to be deleted but found %v", mock.aliases)
+	}
+
+	if expectedCalls := []string{"list", "create", "list", "update", "delete"}; !reflect.DeepEqual(mock.calls, expectedCalls) {
+		t.Errorf("Expected calls %v but got %v", expectedCalls, mock.calls)
+	}
+}
+
+func Test_firewallAliasLargeWrite(t *testing.T) {
+	aliases := map[string]*pfsenseapi.FirewallAlias{}
+	small := &mockAliasClient{aliases: aliases, maxEntries: largeAliasSize}
+	large := &mockAliasClient{aliases: aliases}
+	client := &providerClient{aliases: small, largeAliases: large}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	targets := make([]interface{}, 50000)
+
+	for i := range targets {
+		targets[i] = map[string]interface{}{"address": fmt.Sprintf("10.%d.%d.%d", i/65536, i/256%256, i%256)}
+	}
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"name":   "blocklist",
+		"type":   "host",
+		"target": targets,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if diags := res.CreateContext(ctx, d, client); diags.HasError() {
+		t.Fatalf("Unable to create alias: %v", diags)
+	}
+
+	if !reflect.DeepEqual(large.calls, []string{"create"}) || slices.Contains(small.calls, "create") {
+		t.Errorf("Expected the alias to be written with the large alias client but calls were %v and %v", small.calls, large.calls)
+	}
+
+	if entries := len(strings.Split(aliases["blocklist"].Address, addressSplitter)); entries != len(targets) {
+		t.Errorf("Expected %d entries but got %d", len(targets), entries)
+	}
+}
+
+// slowAliasClient records how many writes to the same alias overlap, the underlying mock isn't safe for concurrent use
+// so the race detector catches writes that aren't serialized as well.
+type slowAliasClient struct {
+	*mockAliasClient
+	mutex      sync.Mutex
+	inFlight   map[string]int
+	overlapped bool
+}
+
+func (s *slowAliasClient) UpdateAlias(ctx context.Context, name string, request pfsenseapi.FirewallAliasRequest, reload bool) (*pfsenseapi.FirewallAlias, error) {
+	s.mutex.Lock()
+	s.inFlight[name]++
+	s.overlapped = s.overlapped || s.inFlight[name] > 1
+	s.mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+	alias, err := s.mockAliasClient.UpdateAlias(ctx, name, request, reload)
+
+	s.mutex.Lock()
+	s.inFlight[name]--
+	s.mutex.Unlock()
+
+	return alias, err
+}
+
+func Test_firewallAliasConcurrentWrites(t *testing.T) {
+	mock := &mockAliasClient{aliases: map[string]*pfsenseapi.FirewallAlias{
+		"web_servers": {Name: "web_servers", Type: "host", Address: "10.0.0.10"},
+	}}
+	slow := &slowAliasClient{mockAliasClient: mock, inFlight: map[string]int{}}
+	client := &providerClient{aliases: slow}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	for _, address := range []string{"10.0.0.11", "10.0.0.12"} {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"name":   "web_servers",
+			"type":   "host",
+			"target": []interface{}{map[string]interface{}{"address": address}},
+		})
+		d.SetId("web_servers")
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if diags := res.UpdateContext(context.Background(), d, client); diags.HasError() {
+				errs <- fmt.Errorf("%v", diags)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Unable to update alias: %v", err)
+	}
+
+	if slow.overlapped {
+		t.Errorf("Expected writes to the same alias to be serialized")
+	}
+
+	if updates := len(mock.calls); updates != 2 {
+		t.Errorf("Expected both updates to be written but got %v", mock.calls)
+	}
+}
+
+// reloadingAliasClient makes the mock safe for concurrent use and records whether writes that reload the filter overlap,
+// each reload takes a while on pfSense
+type reloadingAliasClient struct {
+	*mockAliasClient
+	mutex      sync.Mutex
+	reloading  int
+	overlapped bool
+}
+
+func (r *reloadingAliasClient) write(reload bool, write func() error) error {
+	r.mutex.Lock()
+
+	if reload {
+		r.reloading++
+		r.overlapped = r.overlapped || r.reloading > 1
+	}
+
+	err := write()
+	r.mutex.Unlock()
+
+	if reload {
+		time.Sleep(5 * time.Millisecond)
+
+		r.mutex.Lock()
+		r.reloading--
+		r.mutex.Unlock()
+	}
+
+	return err
+}
+
+func (r *reloadingAliasClient) ListAliases(ctx context.Context) ([]*pfsenseapi.FirewallAlias, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.mockAliasClient.ListAliases(ctx)
+}
+
+func (r *reloadingAliasClient) CreateAlias(ctx context.Context, request pfsenseapi.FirewallAliasRequest, reload bool) (alias *pfsenseapi.FirewallAlias, err error) {
+	err = r.write(reload, func() error {
+		alias, err = r.mockAliasClient.CreateAlias(ctx, request, reload)
+		return err
+	})
+
+	return alias, err
+}
+
+func (r *reloadingAliasClient) DeleteAlias(ctx context.Context, name string, reload bool) error {
+	return r.write(reload, func() error {
+		return r.mockAliasClient.DeleteAlias(ctx, name, reload)
+	})
+}
+
+func Test_firewallAliasParallelCreates(t *testing.T) {
+	mock := &reloadingAliasClient{mockAliasClient: &mockAliasClient{aliases: map[string]*pfsenseapi.FirewallAlias{}}}
+	client := &providerClient{aliases: mock, autoReload: true}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	// Terraform's default parallelism is 10, twice as many aliases are created
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	start := make(chan struct{})
+	resources := make([]*schema.ResourceData, 20)
+
+	for i := range resources {
+		resources[i] = schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"name":          fmt.Sprintf("alias_%d", i),
+			"type":          "host",
+			"force_destroy": true,
+			"target":        []interface{}{map[string]interface{}{"address": fmt.Sprintf("10.0.0.%d", i)}},
+		})
+
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			<-start
+
+			if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+				errs <- fmt.Errorf("%v", diags)
+			}
+		}(resources[i])
+	}
+
+	close(start)
+	wg.Wait()
+
+	for _, d := range resources {
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+
+			if diags := res.DeleteContext(context.Background(), d, client); diags.HasError() {
+				errs <- fmt.Errorf("%v", diags)
+			}
+		}(d)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Unable to write alias: %v", err)
+	}
+
+	if mock.overlapped {
+		t.Errorf("Expected writes that reload the filter to be serialized")
+	}
+
+	if len(mock.aliases) != 0 {
+		t.Errorf("Expected every alias to be created and deleted but %d are left", len(mock.aliases))
+	}
+}
+
+func Test_namedLocksDifferentNames(t *testing.T) {
+	var locks namedLocks
+
+	unlock := locks.lock("web_servers")
+	defer unlock()
+
+	acquired := make(chan struct{})
+
+	go func() {
+		defer locks.lock("db_servers")()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Errorf("Expected a lock on a different name not to wait")
+	}
+}
+
+func Test_firewallAliasNestedAliases(t *testing.T) {
+	existing := map[string]*pfsenseapi.FirewallAlias{
+		"web_servers": {Name: "web_servers", Type: "host", Address: "10.0.0.10 db_servers"},
+		"db_servers":  {Name: "db_servers", Type: "host", Address: "10.0.1.10 backup.example.com"},
+		"all_servers": {Name: "all_servers", Type: "host", Address: "web_servers"},
+		"loop_a":      {Name: "loop_a", Type: "host", Address: "loop_b"},
+		"loop_b":      {Name: "loop_b", Type: "host", Address: "loop_a"},
+		"web_ports":   {Name: "web_ports", Type: "port", Address: "80 443"},
+		"printers":    {Name: "printers", Type: "host", Address: "printer"},
+	}
+
+	tests := map[string]struct {
+		request pfsenseapi.FirewallAliasRequest
+		err     string
+	}{
+		"nested":           {pfsenseapi.FirewallAliasRequest{Name: "servers", Type: "host", Address: []string{"web_servers", "10.0.2.10"}}, ""},
+		"nested port":      {pfsenseapi.FirewallAliasRequest{Name: "ports", Type: "port", Address: []string{"web_ports", "8080", "9000:9100"}}, ""},
+		"hostname":         {pfsenseapi.FirewallAliasRequest{Name: "hosts", Type: "host", Address: []string{"www.example.com"}}, ""},
+		"existing entries": {pfsenseapi.FirewallAliasRequest{Name: "office", Type: "host", Address: []string{"printers"}}, ""},
+		"existing loop":    {pfsenseapi.FirewallAliasRequest{Name: "looped", Type: "host", Address: []string{"loop_a"}}, ""},
+		"missing":          {pfsenseapi.FirewallAliasRequest{Name: "servers", Type: "host", Address: []string{"app_servers"}}, "refers to app_servers which isn't an alias"},
+		"self":             {pfsenseapi.FirewallAliasRequest{Name: "servers", Type: "host", Address: []string{"servers"}}, "servers -> servers"},
+		"through another":  {pfsenseapi.FirewallAliasRequest{Name: "db_servers", Type: "host", Address: []string{"all_servers"}}, "db_servers -> all_servers -> web_servers -> db_servers"},
+		"short host names": {pfsenseapi.FirewallAliasRequest{Name: "hosts", Type: "host", Address: []string{"web_servers", "nas"}}, "refers to nas which isn't an alias, hostnames have to be fully qualified"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			aliases := map[string]*pfsenseapi.FirewallAlias{}
+
+			for key, alias := range existing {
+				aliases[key] = alias
+			}
+
+			client := &providerClient{aliases: &mockAliasClient{aliases: aliases}}
+			r := resourceFirewallAlias()
+
+			var err error
+
+			if _, exists := aliases[test.request.Name]; exists {
+				_, err = r.update(context.Background(), client, test.request.Name, &test.request)
+			} else {
+				_, err = r.create(context.Background(), client, &test.request)
+			}
+
+			if test.err == "" && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected error %q but got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func Test_firewallAliasNestedImportRoundTrip(t *testing.T) {
+	alias := &pfsenseapi.FirewallAlias{Name: "all_servers", Type: "host", Address: "web_servers 10.0.0.10", Detail: "web||db"}
+
+	config, request := importRoundTrip(t, resourceFirewallAlias(), alias)
+
+	if !reflect.DeepEqual(request.Address, []string{"web_servers", "10.0.0.10"}) {
+		t.Errorf("Expected the nested alias to be kept as a name but generated config %v gave %v", config, request.Address)
+	}
+}
+
+func Test_firewallAliasIdIsName(t *testing.T) {
+	mock := &mockAliasClient{aliases: map[string]*pfsenseapi.FirewallAlias{}}
+	client := &providerClient{aliases: mock}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	config := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":          name,
+			"type":          "host",
+			"force_destroy": true,
+			"target":        []interface{}{map[string]interface{}{"address": "10.0.0.10"}},
+		}
+	}
+
+	// Creating each alias in a for_each map one after the other, later keys mustn't touch the earlier aliases
+	states := map[string]*terraform.InstanceState{}
+
+	for _, name := range []string{"web_servers", "db_servers", "app_servers"} {
+		mock.calls = nil
+		d := schema.TestResourceDataRaw(t, res.Schema, config(name))
+
+		if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("Unable to create %s: %v", name, diags)
+		}
+
+		if d.Id() != name {
+			t.Errorf("Expected the ID of %s to be its name but got %s", name, d.Id())
+		}
+
+		if !reflect.DeepEqual(mock.calls, []string{"list", "create"}) {
+			t.Errorf("Expected only %s to be created but got calls %v", name, mock.calls)
+		}
+
+		states[name] = d.State()
+	}
+
+	// Renaming changes the ID so the alias can still be read
+	resourceConfig := terraform.NewResourceConfigRaw(config("api_servers"))
+	diff, err := res.Diff(context.Background(), states["db_servers"], resourceConfig, client)
+
+	if err != nil {
+		t.Fatalf("Unable to plan the rename: %v", err)
+	}
+
+	state, diags := res.Apply(context.Background(), states["db_servers"], diff, client)
+
+	if diags.HasError() {
+		t.Fatalf("Unable to rename: %v", diags)
+	}
+
+	if state.ID != "api_servers" {
+		t.Errorf("Expected the ID to follow the rename but got %s", state.ID)
+	}
+
+	if _, exists := mock.aliases["api_servers"]; !exists || len(mock.aliases) != 3 {
+		t.Errorf("Expected db_servers to be renamed but got %v", mock.aliases)
+	}
+
+	if _, err := res.RefreshWithoutUpgrade(context.Background(), state, client); err != nil {
+		t.Errorf("Unable to read the renamed alias: %v", err)
+	}
+}
+
+func Test_validateAliasName(t *testing.T) {
+	tests := map[string]string{
+		"web_servers":           "",
+		"servers_2":             "",
+		strings.Repeat("a", 31): "",
+		strings.Repeat("a", 32): "is 32 characters long, pfSense allows at most 31",
+		"web-servers":           "can only contain alpha-numeric and underscore characters",
+		"":                      "can only contain alpha-numeric and underscore characters",
+		"12345":                 "can't be only digits or only underscores",
+		"___":                   "can't be only digits or only underscores",
+	}
+
+	for name, expected := range tests {
+		_, errs := validateAliasName(name, "name")
+
+		if expected == "" && len(errs) != 0 {
+			t.Errorf("Expected %q to be valid but got %v", name, errs)
+		} else if expected != "" && (len(errs) != 1 || !strings.Contains(errs[0].Error(), expected)) {
+			t.Errorf("Expected %q to fail with %q but got %v", name, expected, errs)
+		}
+	}
+}
+
+func Test_firewallAliasNameCollision(t *testing.T) {
+	mock := &mockAliasClient{aliases: map[string]*pfsenseapi.FirewallAlias{
+		"web_servers": {Name: "web_servers", Type: "host", Address: "10.0.0.10"},
+		"db_servers":  {Name: "db_servers", Type: "host", Address: "10.0.1.10"},
+	}}
+	client := &providerClient{aliases: mock}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+
+	config := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":   name,
+			"type":   "host",
+			"target": []interface{}{map[string]interface{}{"address": "10.0.2.10"}},
+		}
+	}
+
+	dbServers := &terraform.InstanceState{ID: "db_servers", Attributes: map[string]string{"name": "db_servers", "type": "host", "target.#": "1", "target.0.address": "10.0.1.10"}}
+
+	tests := map[string]struct {
+		state *terraform.InstanceState
+		name  string
+		taken bool
+	}{
+		"new":                {nil, "app_servers", false},
+		"new taken":          {nil, "web_servers", true},
+		"new different case": {nil, "Web_Servers", true},
+		"unchanged":          {dbServers, "db_servers", false},
+		"rename":             {dbServers, "app_servers", false},
+		"rename taken":       {dbServers, "web_servers", true},
+		"rename case":        {dbServers, "DB_servers", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := res.Diff(context.Background(), test.state, terraform.NewResourceConfigRaw(config(test.name)), client)
+
+			if !test.taken && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			} else if test.taken && (err == nil || !strings.Contains(err.Error(), "is already taken by web_servers, an alias this resource doesn't manage")) {
+				t.Errorf("Expected the plan to fail as the name is taken but got %v", err)
+			}
+		})
+	}
+
+	// An alias created after the plan is still caught when the alias is written
+	d := schema.TestResourceDataRaw(t, res.Schema, config("WEB_SERVERS"))
+	diags := res.CreateContext(context.Background(), d, client)
+
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "Alias name WEB_SERVERS is already taken by web_servers") {
+		t.Errorf("Expected the create to fail as the name is taken but got %v", diags)
+	}
+
+	if slices.Contains(mock.calls, "create") || mock.aliases["web_servers"].Address != "10.0.0.10" {
+		t.Errorf("Expected the existing alias to be left alone but got calls %v", mock.calls)
+	}
+
+	request := &pfsenseapi.FirewallAliasRequest{Name: "web_servers", Type: "host", Address: []string{"10.0.1.10"}}
+
+	if _, err := resourceFirewallAlias().update(context.Background(), client, "db_servers", request); err == nil || !strings.Contains(err.Error(), "Alias name web_servers is already taken by web_servers") {
+		t.Errorf("Expected the rename to fail as the name is taken but got %v", err)
+	}
+
+	if slices.Contains(mock.calls, "update") {
+		t.Errorf("Expected no update but got calls %v", mock.calls)
+	}
+}
+
+func Test_aliasWalk(t *testing.T) {
+	// Each alias refers to the next two, walking every path would take 2^n steps
+	var aliases []*pfsenseapi.FirewallAlias
+
+	for i := 0; i < 1000; i++ {
+		aliases = append(aliases, &pfsenseapi.FirewallAlias{
+			Name:    fmt.Sprintf("alias_%d", i),
+			Type:    "host",
+			Address: fmt.Sprintf("alias_%d alias_%d 10.0.%d.%d", i+1, i+2, i/256, i%256),
+		})
+	}
+
+	walked := map[string]int{}
+	w := newAliasWalk(aliases)
+	w.alias = func(alias *pfsenseapi.FirewallAlias) error {
+		walked[alias.Name]++
+		return nil
+	}
+
+	if err := w.walk(nil, []string{"alias_0"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for name, count := range walked {
+		if count != 1 {
+			t.Errorf("Expected %s to be walked once but it was walked %d times", name, count)
+		}
+	}
+
+	entries, err := expandAliasEntries(aliases, "alias_0", []string{"host"})
+
+	if err != nil || len(entries) != 1000+2 {
+		t.Errorf("Expected every address and the two missing aliases once but got %d entries and %v", len(entries), err)
+	}
+
+	loop := []*pfsenseapi.FirewallAlias{
+		{Name: "loop_a", Type: "host", Address: "10.0.0.1 loop_b"},
+		{Name: "loop_b", Type: "host", Address: "loop_c"},
+		{Name: "loop_c", Type: "host", Address: "loop_a"},
+	}
+
+	if err := newAliasWalk(loop).walk(nil, []string{"loop_a"}); err == nil || !strings.Contains(err.Error(), "Alias loop_a refers to itself through loop_a -> loop_b -> loop_c -> loop_a") {
+		t.Errorf("Expected the loop to be reported but got %v", err)
+	}
+
+	if _, err := aliasPrefixes(loop, "loop_b"); err == nil || !strings.Contains(err.Error(), "loop_b -> loop_c -> loop_a -> loop_b") {
+		t.Errorf("Expected the loop to be reported but got %v", err)
+	}
+}
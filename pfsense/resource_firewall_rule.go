@@ -2,32 +2,611 @@ package pfsense
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
 	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
+const (
+	labelSeparator         = ";"
+	labelKeyValueSeparator = "="
+)
+
+// formatLabels serializes labels into a description as key=value pairs sorted by key
+func formatLabels(labels map[string]interface{}) string {
+	keys := make([]string, 0, len(labels))
+
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s%s%v", key, labelKeyValueSeparator, labels[key])
+	}
+
+	return strings.Join(pairs, labelSeparator)
+}
+
+// parseLabels returns nil unless the description is exactly what formatLabels would produce
+func parseLabels(description string) map[string]interface{} {
+	if description == "" {
+		return nil
+	}
+
+	labels := map[string]interface{}{}
+
+	for _, pair := range strings.Split(description, labelSeparator) {
+		key, value, found := strings.Cut(pair, labelKeyValueSeparator)
+
+		if !found || key == "" {
+			return nil
+		}
+
+		labels[key] = value
+	}
+
+	if formatLabels(labels) != description {
+		return nil
+	}
+
+	return labels
+}
+
+func validateLabels(i interface{}, k string) ([]string, []error) {
+	labels, ok := i.(map[string]interface{})
+
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be a map", k)}
+	}
+
+	var errs []error
+
+	for key, value := range labels {
+		if key == "" || strings.Contains(key, labelSeparator) || strings.Contains(key, labelKeyValueSeparator) {
+			errs = append(errs, fmt.Errorf("%s key %q must not be empty or contain %q or %q", k, key, labelSeparator, labelKeyValueSeparator))
+		}
+
+		if strings.Contains(fmt.Sprint(value), labelSeparator) {
+			errs = append(errs, fmt.Errorf("%s value %q for key %q must not contain %q", k, value, key, labelSeparator))
+		}
+	}
+
+	return nil, errs
+}
+
+// descriptionMatchesLabels is true when a plain description is configured that reads back as the labels in state,
+// e.g. `description = "owner=ops"`, which isn't a change to either attribute
+func descriptionMatchesLabels(_, _, _ string, d *schema.ResourceData) bool {
+	oldLabels, newLabels := d.GetChange("labels")
+	old := oldLabels.(map[string]interface{})
+
+	return len(newLabels.(map[string]interface{})) == 0 && len(old) > 0 && formatLabels(old) == d.Get("description").(string)
+}
+
+const (
+	scheduleModeActiveDuring  = "active-during"
+	scheduleModeBlockedDuring = "blocked-during"
+)
+
+// scheduleMode is how pfSense interprets a schedule on the rule, a schedule only ever makes a rule active during the
+// window, so matching traffic is blocked during the window when the rule itself blocks
+func scheduleMode(ruleType string) string {
+	if ruleType == "pass" {
+		return scheduleModeActiveDuring
+	}
+
+	return scheduleModeBlockedDuring
+}
+
+// firewallRuleChecks are the cross field checks on a firewall rule, each one only looks at values known at plan time
+// and returns an error naming the field at fault.
+var firewallRuleChecks = []func(d *schema.ResourceDiff) error{
+	func(d *schema.ResourceDiff) error {
+		if d.Get("quick").(bool) && !d.Get("floating").(bool) {
+			return fmt.Errorf("quick is only available on floating rules, rules on an interface always apply immediately")
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		if direction := d.Get("direction").(string); direction != "any" && !d.Get("floating").(bool) {
+			return fmt.Errorf("direction %s is only available on floating rules, rules on an interface always match inbound traffic", direction)
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		// schedule_mode is computed from the type when it isn't configured
+		if config := d.GetRawConfig(); config.IsNull() || config.GetAttr("schedule_mode").IsNull() {
+			if d.HasChanges("type", "schedule") {
+				return d.SetNewComputed("schedule_mode")
+			}
+		} else if mode := d.Get("schedule_mode").(string); d.NewValueKnown("type") {
+			if d.Get("schedule").(string) == "" && d.NewValueKnown("schedule") {
+				return fmt.Errorf("schedule_mode requires a schedule")
+			}
+
+			if ruleType := d.Get("type").(string); scheduleMode(ruleType) != mode {
+				return fmt.Errorf("schedule_mode %s can't be used with a %s rule, use type = \"pass\" for %s and \"block\" or \"reject\" for %s", mode, ruleType, scheduleModeActiveDuring, scheduleModeBlockedDuring)
+			}
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		if ruleType := d.Get("type").(string); d.Get("gateway").(string) != "" && ruleType != "pass" {
+			return fmt.Errorf("gateway is only available on pass rules, a %s rule doesn't route traffic", ruleType)
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		protocol := d.Get("protocol").(string)
+
+		if !d.NewValueKnown("protocol") || slices.Contains([]string{"tcp", "udp", "tcp/udp"}, protocol) {
+			return nil
+		}
+
+		for _, name := range []string{"source_port", "destination_port"} {
+			if port := d.Get(name).(string); normalizePort(port) != "any" {
+				return fmt.Errorf("%s is only available when protocol is tcp, udp or tcp/udp, not %s", name, protocol)
+			}
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		if protocol := d.Get("protocol").(string); d.NewValueKnown("protocol") && protocol != "icmp" && len(d.Get("icmp_type").([]interface{})) > 0 {
+			return fmt.Errorf("icmp_type is only available when protocol is icmp, not %s", protocol)
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		if protocol := d.Get("protocol").(string); d.NewValueKnown("protocol") && protocol != "tcp" && d.Get("state_type") == "synproxy state" {
+			return fmt.Errorf("state_type synproxy state is only available when protocol is tcp, not %s", protocol)
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		flags := d.Get("tcp_flag").([]interface{})
+
+		if protocol := d.Get("protocol").(string); d.NewValueKnown("protocol") && protocol != "tcp" && len(flags) > 0 {
+			return fmt.Errorf("tcp_flag is only available when protocol is tcp, not %s", protocol)
+		}
+
+		seen := map[string]bool{}
+
+		for _, flag := range flags {
+			name := flag.(map[string]interface{})["flag"].(string)
+
+			if seen[name] {
+				return fmt.Errorf("tcp_flag %s is listed more than once", name)
+			}
+
+			seen[name] = true
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		dnPipe := d.Get("dn_pipe").(string)
+		pdnPipe := d.Get("pdn_pipe").(string)
+
+		if pdnPipe == "" || !d.NewValueKnown("dn_pipe") {
+			return nil
+		}
+
+		if dnPipe == "" {
+			return fmt.Errorf("pdn_pipe requires dn_pipe")
+		}
+
+		if dnPipe == pdnPipe {
+			return fmt.Errorf("pdn_pipe can't be the same as dn_pipe")
+		}
+
+		return nil
+	},
+	func(d *schema.ResourceDiff) error {
+		defaultQueue := d.Get("default_queue").(string)
+		ackQueue := d.Get("ack_queue").(string)
+
+		if ackQueue == "" || !d.NewValueKnown("default_queue") {
+			return nil
+		}
+
+		if defaultQueue == "" {
+			return fmt.Errorf("ack_queue requires default_queue")
+		}
+
+		if defaultQueue == ackQueue {
+			return fmt.Errorf("ack_queue can't be the same as default_queue")
+		}
+
+		return nil
+	},
+}
+
+func validateFirewallRule(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	var errs []error
+
+	for _, check := range firewallRuleChecks {
+		if err := check(d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if client, ok := m.(*providerClient); ok && client.requireUniqueRuleDescriptions {
+		if err := checkUniqueRuleDescription(ctx, client, d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if client, ok := m.(*providerClient); ok {
+		if err := checkRuleAliases(ctx, client, d); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// firewallRuleImportId accepts a tracker or `<interface>/<tracker>` as the import ID. Descriptions aren't unique so
+// they're rejected, listing the rules that have the description to choose from.
+func firewallRuleImportId(ctx context.Context, client *providerClient, id string) (string, error) {
+	if _, err := strconv.Atoi(id); err == nil {
+		return id, nil
+	}
+
+	rules, err := client.rules.get(ctx, client.Firewall.ListRules)
+
+	if err != nil {
+		return "", err
+	}
+
+	if iface, tracker, found := strings.Cut(id, "/"); found {
+		for _, rule := range rules {
+			if fmt.Sprint(rule.Tracker) == tracker && slices.Contains(splitIntoArray(rule.Interface, ","), iface) {
+				return tracker, nil
+			}
+		}
+
+		return "", fmt.Errorf("Unable to find a rule with tracker %s on interface %s", tracker, iface)
+	}
+
+	var candidates []string
+
+	for _, rule := range rules {
+		if rule.Descr == id {
+			candidates = append(candidates, fmt.Sprintf("%s/%d", rule.Interface, rule.Tracker))
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("Import firewall rules by tracker or <interface>/<tracker>, no rule has the description %q", id)
+	}
+
+	return "", fmt.Errorf("Import firewall rules by tracker or <interface>/<tracker>, rules with the description %q are %s", id, strings.Join(candidates, ", "))
+}
+
+// ruleInterfacePattern matches the pfSense interface IDs and their addresses, e.g. lan or opt1ip, along with the other
+// networks pfSense accepts as a rule address
+var ruleInterfacePattern = regexp.MustCompile(`^((wan|lan|opt[0-9]+|pppoe|l2tp)(ip)?|\(self\))$`)
+
+// isRuleInterface is true when the value names an interface or its address, by its pfSense ID, its physical interface
+// or its description. Interfaces are only listed when the value isn't one of the pfSense IDs.
+func isRuleInterface(ctx context.Context, client *providerClient, value string) (bool, error) {
+	if ruleInterfacePattern.MatchString(value) {
+		return true, nil
+	}
+
+	ifaces, err := client.interfaceCache.get(ctx, client.Interface.ListInterfaces)
+
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(ifaces, func(iface *pfsenseapi.Interface) bool {
+		for _, name := range []string{iface.Name, iface.If, iface.Descr} {
+			if name != "" && (value == name || value == name+"ip") {
+				return true
+			}
+		}
+
+		return false
+	}), nil
+}
+
+// editDistance is the number of single character insertions, deletions or substitutions that turn a into b
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// closestAliasName returns the name of the alias closest to the name, ignoring case, or "" when none are close enough to
+// be a typo of it
+func closestAliasName(aliases []*pfsenseapi.FirewallAlias, name string) string {
+	closest := ""
+	closestDistance := max(2, len(name)/3) + 1
+
+	for _, alias := range aliases {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(alias.Name)); distance < closestDistance {
+			closest, closestDistance = alias.Name, distance
+		}
+	}
+
+	return closest
+}
+
+// isRuleAddressName is true when the address can't be anything but an alias or an interface, i.e. it isn't any, an
+// address or a network
+func isRuleAddressName(value string) bool {
+	_, err := netip.ParsePrefix(value)
+
+	return value != "" && value != "any" && err != nil && net.ParseIP(value) == nil
+}
+
+// checkRuleAliases makes sure the aliases the rule refers to are used where their type fits, port aliases on the ports
+// and host or network aliases on the addresses. Names that aren't aliases yet are left to checkRuleAddresses when the
+// rule is written, the alias could be created in the same apply. Only values that can't be anything but an alias
+// cause the aliases to be listed, the list is cached for the rest of the plan.
+func checkRuleAliases(ctx context.Context, client *providerClient, d *schema.ResourceDiff) error {
+	candidates := map[string]string{}
+
+	for _, name := range []string{"source", "destination"} {
+		if value := strings.TrimPrefix(d.Get(name).(string), "!"); d.NewValueKnown(name) && isRuleAddressName(value) {
+			candidates[name] = value
+		}
+	}
+
+	for _, name := range []string{"source_port", "destination_port"} {
+		if value := d.Get(name).(string); d.NewValueKnown(name) && normalizePort(value) != "any" && !isPortOrRange(value) {
+			candidates[name] = value
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	aliases, err := client.aliasCache.get(ctx, client.aliases.ListAliases)
+
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, name := range []string{"source", "destination", "source_port", "destination_port"} {
+		alias := findAlias(aliases, candidates[name])
+
+		if alias == nil {
+			continue
+		}
+
+		if isPort := strings.HasSuffix(name, "_port"); isPort && alias.Type != "port" {
+			errs = append(errs, fmt.Errorf("%s refers to %s alias %s, only port aliases can be used on ports", name, alias.Type, alias.Name))
+		} else if !isPort && alias.Type == "port" {
+			errs = append(errs, fmt.Errorf("%s refers to port alias %s, use it as %s_port instead", name, alias.Name, name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkRuleAddresses makes sure the names the rule's addresses refer to are aliases or interfaces when the rule is
+// written. pfSense takes an address it doesn't know as a literal, so a misspelt alias would otherwise match nothing.
+func checkRuleAddresses(ctx context.Context, client *providerClient, request *pfsenseapi.FirewallRuleRequest) error {
+	var errs []error
+
+	for name, address := range map[string]string{"source": request.Src, "destination": request.Dst} {
+		value := strings.TrimPrefix(address, "!")
+
+		if !isRuleAddressName(value) {
+			continue
+		}
+
+		aliases, err := client.aliasCache.get(ctx, client.aliases.ListAliases)
+
+		if err != nil {
+			return err
+		}
+
+		if findAlias(aliases, value) != nil {
+			continue
+		}
+
+		if isInterface, err := isRuleInterface(ctx, client, value); err != nil {
+			return err
+		} else if isInterface {
+			continue
+		}
+
+		if closest := closestAliasName(aliases, value); closest != "" {
+			errs = append(errs, fmt.Errorf("%s refers to %s, which isn't an alias or interface, did you mean %s?", name, value, closest))
+		} else {
+			errs = append(errs, fmt.Errorf("%s refers to %s, which isn't an alias or interface", name, value))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkUniqueRuleDescription makes sure the rule has a description and that no other rule on the same interfaces has
+// the same one. Rules that are only planned aren't on the firewall yet, so two new rules can still share a description.
+func checkUniqueRuleDescription(ctx context.Context, client *providerClient, d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("description") || !d.NewValueKnown("labels") || !d.NewValueKnown("interface") {
+		return nil
+	}
+
+	description := d.Get("description").(string)
+
+	if labels := d.Get("labels").(map[string]interface{}); len(labels) > 0 {
+		description = formatLabels(labels)
+	}
+
+	if description == "" {
+		return fmt.Errorf("description or labels are required when require_unique_rule_descriptions is set")
+	}
+
+	rules, err := client.rules.get(ctx, client.Firewall.ListRules)
+
+	if err != nil {
+		return err
+	}
+
+	interfaces, err := interfaceToStringArray(d.Get("interface"))
+
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if fmt.Sprint(rule.Tracker) == d.Id() || rule.Descr != description {
+			continue
+		}
+
+		for _, iface := range splitIntoArray(rule.Interface, ",") {
+			if slices.Contains(interfaces, iface) {
+				return fmt.Errorf("Rule %d on interface %s already has the description %q", rule.Tracker, iface, description)
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizePort maps the ways of saying any port to `any`, pfSense treats an empty port and `any` the same
+func normalizePort(port string) string {
+	if port == "" || strings.EqualFold(port, "any") {
+		return "any"
+	}
+
+	return port
+}
+
+func portDiffSuppress(_, oldValue, newValue string, _ *schema.ResourceData) bool {
+	return normalizePort(oldValue) == normalizePort(newValue)
+}
+
+// builtinRuleInterfaces are the interfaces rules can use that aren't assigned interfaces or groups
+var builtinRuleInterfaces = []string{"openvpn", "ipsec", "l2tp", "pppoe"}
+
+// checkRuleInterfaces makes sure each of the rule's interfaces exists, pfSense accepts a rule on an interface it
+// doesn't have and the rule never matches anything.
+func checkRuleInterfaces(ctx context.Context, client *providerClient, request *pfsenseapi.FirewallRuleRequest) error {
+	interfaces, err := client.interfaceCache.get(ctx, client.Interface.ListInterfaces)
+
+	if err != nil {
+		return err
+	}
+
+	groups, err := client.interfaceGroupCache.get(ctx, client.Interface.ListInterfaceGroups)
+
+	if err != nil {
+		return err
+	}
+
+	valid := slices.Clone(builtinRuleInterfaces)
+
+	for _, iface := range interfaces {
+		valid = append(valid, iface.Name, iface.Descr, iface.If)
+	}
+
+	for _, group := range groups {
+		valid = append(valid, group.Ifname)
+	}
+
+	for _, name := range request.Interface {
+		if !slices.Contains(valid, name) {
+			slices.Sort(valid)
+			valid = slices.DeleteFunc(slices.Compact(valid), func(v string) bool { return v == "" })
+
+			return fmt.Errorf("Interface %s doesn't exist, valid interfaces are %s", name, strings.Join(valid, ", "))
+		}
+	}
+
+	return nil
+}
+
 func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi.FirewallRule, int] {
 	return &resource[pfsenseapi.FirewallRuleRequest, pfsenseapi.FirewallRule, int]{
 		name:        "pfsense_firewall_rule",
-		description: "Firewall Rule",
-		delete: func(ctx context.Context, client *pfsenseapi.Client, _ string, id int) error {
-			return client.Firewall.DeleteRule(ctx, id, true)
+		description: "Firewall Rule, imported by its tracker or `<interface>/<tracker>`.",
+		delete: func(ctx context.Context, client *providerClient, _ string, id int) error {
+			defer client.rules.invalidate()
+			return client.Firewall.DeleteRule(ctx, id, client.autoReload)
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, _ string) ([]*pfsenseapi.FirewallRule, error) {
-			return client.Firewall.ListRules(ctx)
+		list: func(ctx context.Context, client *providerClient, _ string) ([]*pfsenseapi.FirewallRule, error) {
+			return client.rules.get(ctx, client.Firewall.ListRules)
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, id int, request *pfsenseapi.FirewallRuleRequest) (*pfsenseapi.FirewallRule, error) {
-			return client.Firewall.UpdateRule(ctx, id, *request, true)
+		update: func(ctx context.Context, client *providerClient, id int, request *pfsenseapi.FirewallRuleRequest) (*pfsenseapi.FirewallRule, error) {
+			if err := checkRuleInterfaces(ctx, client, request); err != nil {
+				return nil, err
+			}
+
+			if err := checkRuleAddresses(ctx, client, request); err != nil {
+				return nil, err
+			}
+
+			defer client.rules.invalidate()
+			return client.Firewall.UpdateRule(ctx, id, *request, client.autoReload)
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.FirewallRuleRequest) (*pfsenseapi.FirewallRule, error) {
-			return client.Firewall.CreateRule(ctx, *request, true)
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.FirewallRuleRequest) (*pfsenseapi.FirewallRule, error) {
+			if err := checkRuleInterfaces(ctx, client, request); err != nil {
+				return nil, err
+			}
+
+			if err := checkRuleAddresses(ctx, client, request); err != nil {
+				return nil, err
+			}
+
+			defer client.rules.invalidate()
+			return client.Firewall.CreateRule(ctx, *request, client.autoReload)
 		},
-		getId: func(_ context.Context, _ *pfsenseapi.Client, response *pfsenseapi.FirewallRule) (int, error) {
+		getId: func(_ context.Context, _ *providerClient, response *pfsenseapi.FirewallRule) (int, error) {
 			return int(response.Tracker), nil
 		},
+		customizeDiff:     validateFirewallRule,
+		importId:          firewallRuleImportId,
+		clearEmptyStrings: true,
 		properties: map[string]*resourceProperty[pfsenseapi.FirewallRuleRequest, pfsenseapi.FirewallRule]{
 			"ack_queue": {
 				schema: &schema.Schema{
@@ -59,24 +638,30 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 			},
 			"description": {
 				schema: &schema.Schema{
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Description for the rule.",
+					Type:          schema.TypeString,
+					Optional:      true,
+					Description:   "Description for the rule.",
+					ConflictsWith: []string{"labels"},
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
 					req.Descr = d.Get(name).(string)
 					return nil
 				},
 				getFromResponse: func(res *pfsenseapi.FirewallRule) (interface{}, error) {
+					if parseLabels(res.Descr) != nil {
+						return nil, nil
+					}
+
 					return res.Descr, nil
 				},
+				diffSuppress: descriptionMatchesLabels,
 			},
 			"direction": {
 				schema: &schema.Schema{
 					Type:         schema.TypeString,
 					Optional:     true,
 					Default:      "any",
-					Description:  "Direction of floating firewall rule. This parameter is only avilable when `floating` is set to `true`.",
+					Description:  "Direction of floating firewall rule. This parameter is only available when `floating` is set to `true`, rules on an interface always match inbound traffic.",
 					ValidateFunc: validation.StringInSlice([]string{"in", "out", "any"}, false),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
@@ -140,10 +725,10 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 					Type:        schema.TypeString,
 					Optional:    true,
 					Default:     "any",
-					Description: "TCP and/or UDP destination port, port range or port alias to apply to this rule. You may specify `any` to match any destination port. This parameter is required when `protocol` is set to `tcp`, `udp`, or `tcp/udp`.",
+					Description: "TCP and/or UDP destination port, port range or port alias to apply to this rule. You may specify `any` or leave it empty to match any destination port. Other values are only available when `protocol` is set to `tcp`, `udp`, or `tcp/udp`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
-					req.DstPort = d.Get(name).(string)
+					req.DstPort = normalizePort(d.Get(name).(string))
 					return nil
 				},
 				getFromResponse: func(res *pfsenseapi.FirewallRule) (interface{}, error) {
@@ -152,6 +737,7 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 					}
 					return res.Destination.Port, nil
 				},
+				diffSuppress: portDiffSuppress,
 			},
 			"floating": {
 				schema: &schema.Schema{
@@ -170,10 +756,9 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 			},
 			"gateway": {
 				schema: &schema.Schema{
-					Type:         schema.TypeString,
-					Optional:     true,
-					Description:  "Name of an existing gateway traffic will route over upon match. Do not specify this parameter to assume the default gateway. The gateway specified must be of the same IP type set in `ipprotocol`.",
-					ValidateFunc: validation.IsIPAddress,
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Name of an existing gateway traffic will route over upon match. Do not specify this parameter to assume the default gateway. The gateway specified must be of the same IP type set in `ipprotocol`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
 					req.Gateway = d.Get(name).(string)
@@ -211,7 +796,7 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 				schema: &schema.Schema{
 					Type:        schema.TypeList,
 					Required:    true,
-					Description: "Interface this rule will apply to. You may specify either the interface's descriptive name, the pfSense  interface ID (e.g. wan, lan, optx), or the real interface ID (e.g. igb0). If `floating` is enabled, multiple interfaces may be specified.",
+					Description: "Interface this rule will apply to. You may specify either the interface's descriptive name, the pfSense  interface ID (e.g. wan, lan, optx), or the real interface ID (e.g. igb0). If `floating` is enabled, multiple interfaces may be specified. Each interface must exist or be an interface group, this is checked when the rule is created or updated.",
 					Elem: &schema.Schema{
 						Type: schema.TypeString,
 					},
@@ -246,6 +831,26 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 					return res.IPProtocol, nil
 				},
 			},
+			"labels": {
+				schema: &schema.Schema{
+					Type:          schema.TypeMap,
+					Optional:      true,
+					Description:   "Metadata stored in the rule description as `key=value` pairs separated by `;` and sorted by key. Keys can't contain `=` or `;` and values can't contain `;`.",
+					ConflictsWith: []string{"description"},
+					ValidateFunc:  validateLabels,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+				},
+				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
+					req.Descr = formatLabels(d.Get(name).(map[string]interface{}))
+					return nil
+				},
+				getFromResponse: func(res *pfsenseapi.FirewallRule) (interface{}, error) {
+					return parseLabels(res.Descr), nil
+				},
+				diffSuppress: descriptionMatchesLabels,
+			},
 			"log": {
 				schema: &schema.Schema{
 					Type:        schema.TypeBool,
@@ -296,7 +901,7 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 					Type:        schema.TypeBool,
 					Optional:    true,
 					Default:     false,
-					Description: "Apply action immediately upon match. This field is only available for `floating` rules.",
+					Description: "Apply action immediately upon match instead of on the last matching rule. This field is only available for `floating` rules, non floating rules are always quick.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
 					req.Quick = d.Get(name).(bool)
@@ -320,6 +925,22 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 					return res.Sched, nil
 				},
 			},
+			"schedule_mode": {
+				schema: &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					Description:  "How `schedule` affects matching traffic, pfSense only applies the rule during the schedule. `active-during` passes traffic during the schedule and requires `type` to be `pass`. `blocked-during` blocks traffic during the schedule, e.g. for a maintenance window, and requires `type` to be `block` or `reject`. Computed from `type` when not set.",
+					ValidateFunc: validation.StringInSlice([]string{scheduleModeActiveDuring, scheduleModeBlockedDuring}, false),
+				},
+				getFromResponse: func(res *pfsenseapi.FirewallRule) (interface{}, error) {
+					if res.Sched == "" {
+						return nil, nil
+					}
+
+					return scheduleMode(res.Type), nil
+				},
+			},
 			"source": {
 				schema: &schema.Schema{
 					Type:        schema.TypeString,
@@ -344,10 +965,10 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 					Type:        schema.TypeString,
 					Optional:    true,
 					Default:     "any",
-					Description: "TCP and/or UDP source port, port range or port alias  to apply to this rule. You may specify `any` to match any source port. This parameter is required when `protocol` is set to `tcp`, `udp`, or `tcp/udp`.",
+					Description: "TCP and/or UDP source port, port range or port alias  to apply to this rule. You may specify `any` or leave it empty to match any source port. Other values are only available when `protocol` is set to `tcp`, `udp`, or `tcp/udp`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.FirewallRuleRequest) error {
-					req.SrcPort = d.Get(name).(string)
+					req.SrcPort = normalizePort(d.Get(name).(string))
 					return nil
 				},
 				getFromResponse: func(res *pfsenseapi.FirewallRule) (interface{}, error) {
@@ -357,6 +978,7 @@ func resourceFirewallRule() *resource[pfsenseapi.FirewallRuleRequest, pfsenseapi
 
 					return res.Source.Port, nil
 				},
+				diffSuppress: portDiffSuppress,
 			},
 			"state_type": {
 				schema: &schema.Schema{
@@ -0,0 +1,242 @@
+package pfsense
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+// rulesMatchingLabels returns the rules whose labels include every one of the selector's labels
+func rulesMatchingLabels(rules []*pfsenseapi.FirewallRule, selector map[string]interface{}) []*pfsenseapi.FirewallRule {
+	var matches []*pfsenseapi.FirewallRule
+
+outer:
+	for _, rule := range rules {
+		labels := parseLabels(rule.Descr)
+
+		for key, value := range selector {
+			if labels[key] != value {
+				continue outer
+			}
+		}
+
+		matches = append(matches, rule)
+	}
+
+	return matches
+}
+
+// setRuleDisabled writes the rule back with only disabled changed
+func setRuleDisabled(ctx context.Context, client *providerClient, rule *pfsenseapi.FirewallRule, disabled bool) error {
+	request, err := resourceFirewallRule().requestFromResponse(rule)
+
+	if err != nil {
+		return fmt.Errorf("Unable to read rule %d: %v", rule.Tracker, err)
+	}
+
+	request.Disabled = disabled
+
+	if _, err := client.Firewall.UpdateRule(ctx, int(rule.Tracker), *request, client.autoReload); err != nil {
+		return fmt.Errorf("Unable to update rule %d: %v", rule.Tracker, err)
+	}
+
+	return nil
+}
+
+// bulkStateDryRun reports the rules that would have been disabled or enabled in dry run mode. The list is a warning
+// and the apply still fails, so Terraform doesn't record a change that wasn't made.
+func bulkStateDryRun(action string, rules []*pfsenseapi.FirewallRule, disabled bool) diag.Diagnostics {
+	state := "enabled"
+
+	if disabled {
+		state = "disabled"
+	}
+
+	var changes []string
+
+	for _, rule := range rules {
+		changes = append(changes, fmt.Sprintf("%d (%s)", rule.Tracker, rule.Descr))
+	}
+
+	if len(changes) == 0 {
+		changes = append(changes, "none")
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Dry run, rules that would have been %s", state),
+			Detail:   strings.Join(changes, "\n"),
+		},
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Dry run, pfsense_firewall_rule_bulk_state not %s", action),
+		},
+	}
+}
+
+func resourceFirewallRuleBulkState() *schema.Resource {
+	return &schema.Resource{
+		Description: "Disables (or enables) every firewall rule whose labels include `labels`, e.g. during maintenance, and puts the rules it changed back when it's destroyed. " +
+			"Rules that are already in the requested state aren't touched or restored. Rules managed by `pfsense_firewall_rule` will show the change as drift unless `disabled` is in their `ignore_changes`.",
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+			disabled := d.Get("disabled").(bool)
+			selector := d.Get("labels").(map[string]interface{})
+
+			if len(selector) == 0 {
+				return diag.Errorf("At least one label is needed to select the rules")
+			}
+
+			rules, err := client.rules.get(ctx, client.Firewall.ListRules)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			matches := rulesMatchingLabels(rules, selector)
+
+			if len(matches) == 0 && !d.Get("allow_empty").(bool) {
+				return diag.Errorf("No firewall rules have the labels %s, set allow_empty to allow this", formatLabels(selector))
+			}
+
+			if client.dryRun {
+				var changes []*pfsenseapi.FirewallRule
+
+				for _, rule := range matches {
+					if rule.Disabled != disabled {
+						changes = append(changes, rule)
+					}
+				}
+
+				return bulkStateDryRun("created", changes, disabled)
+			}
+
+			defer client.rules.invalidate()
+
+			// The ID is set first so the rules changed so far are saved, and restored on destroy, if a write fails
+			d.SetId(fmt.Sprintf("%s/%t", formatLabels(selector), disabled))
+			trackers := []int{}
+
+			for _, rule := range matches {
+				if rule.Disabled == disabled {
+					continue
+				}
+
+				if err := setRuleDisabled(ctx, client, rule, disabled); err != nil {
+					return diag.FromErr(err)
+				}
+
+				trackers = append(trackers, int(rule.Tracker))
+
+				if err := d.Set("trackers", trackers); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+
+			if err := d.Set("trackers", trackers); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+
+			rules, err := client.rules.get(ctx, client.Firewall.ListRules)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			// Rules deleted since can't be restored
+			trackers := []int{}
+
+			for _, tracker := range d.Get("trackers").([]interface{}) {
+				if slices.ContainsFunc(rules, func(rule *pfsenseapi.FirewallRule) bool { return int(rule.Tracker) == tracker.(int) }) {
+					trackers = append(trackers, tracker.(int))
+				}
+			}
+
+			if err := d.Set("trackers", trackers); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+			disabled := d.Get("disabled").(bool)
+
+			rules, err := client.rules.get(ctx, client.Firewall.ListRules)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			var changes []*pfsenseapi.FirewallRule
+
+			for _, rule := range rules {
+				// Rules changed since by something else are left alone
+				if slices.Contains(d.Get("trackers").([]interface{}), interface{}(int(rule.Tracker))) && rule.Disabled == disabled {
+					changes = append(changes, rule)
+				}
+			}
+
+			if client.dryRun {
+				return bulkStateDryRun("deleted", changes, !disabled)
+			}
+
+			defer client.rules.invalidate()
+
+			for _, rule := range changes {
+				if err := setRuleDisabled(ctx, client, rule, !disabled); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+
+			d.SetId("")
+
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"labels": {
+				Type:         schema.TypeMap,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateLabels,
+				Description:  "Labels that select the rules, a rule matches when its `labels` include all of these.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "State to put the matching rules in, `true` disables them and `false` enables them.",
+			},
+			"allow_empty": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Allow the labels to match no rules, otherwise creating the resource fails.",
+			},
+			"trackers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Trackers of the rules that were changed, these are restored when the resource is destroyed.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package pfsense
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+func resourceFirewallRuleBulkStateTest() resourceTest {
+	return &schemaResourceTest{
+		name:     "pfsense_firewall_rule_bulk_state",
+		resource: resourceFirewallRuleBulkState(),
+	}
+}
+
+// ruleServer serves the rules and applies the updates made to them
+func ruleServer(t *testing.T, rules []*pfsenseapi.FirewallRule) (*providerClient, *[]pfsenseapi.FirewallRuleRequest) {
+	updates := []pfsenseapi.FirewallRuleRequest{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := []byte("null")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/firewall/rule":
+			data, _ = json.Marshal(rules)
+		case "PUT /api/v1/firewall/rule":
+			var request struct {
+				pfsenseapi.FirewallRuleRequest
+				Tracker int `json:"tracker"`
+			}
+
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("Invalid update %v", err)
+			}
+
+			updates = append(updates, request.FirewallRuleRequest)
+
+			for _, rule := range rules {
+				if int(rule.Tracker) == request.Tracker {
+					rule.Disabled = request.Disabled
+					data, _ = json.Marshal(rule)
+				}
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + string(data) + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false), &updates
+}
+
+func Test_firewallRuleBulkState(t *testing.T) {
+	rules := []*pfsenseapi.FirewallRule{
+		{Tracker: 1, Type: "pass", Interface: "lan", Protocol: "tcp", Descr: "service=web;stage=prod", Source: &pfsenseapi.FirewallTarget{Any: true}, Destination: &pfsenseapi.FirewallTarget{Address: "10.0.0.10", Port: "443"}},
+		{Tracker: 2, Type: "pass", Interface: "lan", Descr: "service=web", Disabled: true, Source: &pfsenseapi.FirewallTarget{Any: true}, Destination: &pfsenseapi.FirewallTarget{Any: true}},
+		{Tracker: 3, Type: "pass", Interface: "lan", Descr: "service=dns", Source: &pfsenseapi.FirewallTarget{Any: true}, Destination: &pfsenseapi.FirewallTarget{Any: true}},
+	}
+
+	client, updates := ruleServer(t, rules)
+	r := resourceFirewallRuleBulkState()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"labels": map[string]interface{}{"service": "mail"}})
+
+	if diags := r.CreateContext(context.Background(), d, client); !diags.HasError() {
+		t.Errorf("Expected an error when no rules match")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"labels": map[string]interface{}{"service": "web"}})
+
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	if trackers := d.Get("trackers"); !reflect.DeepEqual(trackers, []interface{}{1}) {
+		t.Errorf("Expected only the enabled web rule to be changed but got %v", trackers)
+	}
+
+	if !rules[0].Disabled || !rules[1].Disabled || rules[2].Disabled {
+		t.Errorf("Expected only the web rules to be disabled")
+	}
+
+	if len(*updates) != 1 || (*updates)[0].Descr != "service=web;stage=prod" || (*updates)[0].DstPort != "443" || (*updates)[0].Dst != "10.0.0.10" {
+		t.Errorf("Expected the rule to be written back unchanged apart from disabled but got %+v", *updates)
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	if rules[0].Disabled || !rules[1].Disabled {
+		t.Errorf("Expected only the rule that was changed to be enabled again")
+	}
+}
+
+func Test_firewallRuleBulkStateDryRun(t *testing.T) {
+	rules := []*pfsenseapi.FirewallRule{
+		{Tracker: 1, Type: "pass", Interface: "lan", Descr: "service=web;stage=prod", Source: &pfsenseapi.FirewallTarget{Any: true}, Destination: &pfsenseapi.FirewallTarget{Any: true}},
+		{Tracker: 2, Type: "pass", Interface: "lan", Descr: "service=web", Disabled: true, Source: &pfsenseapi.FirewallTarget{Any: true}, Destination: &pfsenseapi.FirewallTarget{Any: true}},
+	}
+
+	client, updates := ruleServer(t, rules)
+	client.dryRun = true
+	r := resourceFirewallRuleBulkState()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"labels": map[string]interface{}{"service": "web"}})
+	diags := r.CreateContext(context.Background(), d, client)
+
+	if !diags.HasError() || d.Id() != "" {
+		t.Errorf("Expected the create to fail without an ID in dry run mode but got %v", diags)
+	}
+
+	if diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "1 (service=web;stage=prod)") || strings.Contains(diags[0].Detail, "2 (") {
+		t.Errorf("Expected a warning listing only the rule that would be disabled but got %v", diags)
+	}
+
+	d.SetId("service=web/true")
+
+	if err := d.Set("trackers", []int{1}); err != nil {
+		t.Fatalf("Unable to set trackers: %v", err)
+	}
+
+	rules[0].Disabled = true
+	client.rules.invalidate()
+
+	if diags := r.DeleteContext(context.Background(), d, client); !diags.HasError() || !strings.Contains(diags[0].Detail, "1 (service=web;stage=prod)") {
+		t.Errorf("Expected the delete to fail listing the rule that would be enabled but got %v", diags)
+	}
+
+	if len(*updates) != 0 || !rules[0].Disabled || !rules[1].Disabled {
+		t.Errorf("Expected no rules to be updated in dry run mode but got %+v", *updates)
+	}
+}
@@ -1,6 +1,16 @@
 package pfsense
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
@@ -9,3 +19,692 @@ func resourceFirewallRuleTest() resourceTest {
 		resource: resourceFirewallRule(),
 	}
 }
+
+func Test_firewallRuleImportRoundTrip(t *testing.T) {
+	rule := new(pfsenseapi.FirewallRule)
+
+	err := json.Unmarshal([]byte(`{
+		"tracker": "1700000001",
+		"type": "pass",
+		"interface": "lan",
+		"ipprotocol": "inet",
+		"protocol": "tcp",
+		"source": {"address": "10.0.0.0/24", "port": "1024:65535"},
+		"destination": {"address": "WEB_SERVERS", "port": "443"},
+		"descr": "Allow web traffic",
+		"gateway": "WAN_DHCP",
+		"sched": "business_hours",
+		"statetype": "keep state",
+		"tcpflags1": "syn",
+		"tcpflags2": "syn,ack",
+		"log": ""
+	}`), rule)
+
+	if err != nil {
+		t.Fatalf("Unable to parse rule: %v", err)
+	}
+
+	config, request := importRoundTrip(t, resourceFirewallRule(), rule)
+
+	expected := pfsenseapi.FirewallRuleRequest{
+		Descr:      "Allow web traffic",
+		Direction:  "any",
+		Dst:        "WEB_SERVERS",
+		DstPort:    "443",
+		Gateway:    "WAN_DHCP",
+		Interface:  []string{"lan"},
+		IPProtocol: "inet",
+		Log:        true,
+		Protocol:   "tcp",
+		Sched:      "business_hours",
+		Src:        "10.0.0.0/24",
+		SrcPort:    "1024:65535",
+		StateType:  "keep state",
+		TCPFlags1:  []string{"syn"},
+		TCPFlags2:  []string{"syn", "ack"},
+		Type:       "pass",
+	}
+
+	if !reflect.DeepEqual(*request, expected) {
+		t.Errorf("Generated config %v did not round trip, expected %+v but got %+v", config, expected, *request)
+	}
+}
+
+func Test_parseLabels(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    map[string]interface{}
+	}{
+		{"", nil},
+		{"Allow web traffic", nil},
+		{"owner=ops", map[string]interface{}{"owner": "ops"}},
+		{"cost_center=42;owner=ops", map[string]interface{}{"cost_center": "42", "owner": "ops"}},
+		{"owner=ops;cost_center=42", nil},
+		{"owner=ops;", nil},
+		{"=ops", nil},
+		{"url=https://x?a=b", map[string]interface{}{"url": "https://x?a=b"}},
+	}
+
+	for _, test := range tests {
+		actual := parseLabels(test.description)
+
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Expected %q to parse as %v but got %v", test.description, test.expected, actual)
+		}
+
+		if actual != nil && formatLabels(actual) != test.description {
+			t.Errorf("Expected %v to format as %q but got %q", actual, test.description, formatLabels(actual))
+		}
+	}
+}
+
+func Test_validateLabels(t *testing.T) {
+	if _, errs := validateLabels(map[string]interface{}{"owner": "ops", "url": "a=b"}, "labels"); len(errs) != 0 {
+		t.Errorf("Expected valid labels but got %v", errs)
+	}
+
+	for _, labels := range []map[string]interface{}{{"a;b": "c"}, {"a=b": "c"}, {"a": "b;c"}, {"": "c"}} {
+		if _, errs := validateLabels(labels, "labels"); len(errs) == 0 {
+			t.Errorf("Expected %v to be invalid", labels)
+		}
+	}
+}
+
+func Test_firewallRuleLabelsImportRoundTrip(t *testing.T) {
+	rule := &pfsenseapi.FirewallRule{
+		Type:      "pass",
+		Interface: "lan",
+		Descr:     "cost_center=42;owner=ops",
+	}
+
+	config, request := importRoundTrip(t, resourceFirewallRule(), rule)
+
+	if _, exists := config["description"]; exists {
+		t.Errorf("Expected labels rather than a description in generated config %v", config)
+	}
+
+	if request.Descr != rule.Descr {
+		t.Errorf("Expected description %q but got %q", rule.Descr, request.Descr)
+	}
+}
+
+func Test_validateFirewallRule(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"minimal":                   {map[string]interface{}{}, ""},
+		"quick floating":            {map[string]interface{}{"floating": true, "quick": true}, ""},
+		"floating":                  {map[string]interface{}{"floating": true}, ""},
+		"quick":                     {map[string]interface{}{"quick": true}, "quick"},
+		"direction floating":        {map[string]interface{}{"floating": true, "direction": "out"}, ""},
+		"direction any":             {map[string]interface{}{"direction": "any"}, ""},
+		"direction":                 {map[string]interface{}{"direction": "in"}, "direction in is only available on floating rules"},
+		"gateway pass":              {map[string]interface{}{"gateway": "WAN_DHCP"}, ""},
+		"gateway block":             {map[string]interface{}{"type": "block", "gateway": "WAN_DHCP"}, "gateway"},
+		"gateway reject":            {map[string]interface{}{"type": "reject", "gateway": "WAN_DHCP"}, "gateway"},
+		"ports tcp":                 {map[string]interface{}{"protocol": "tcp", "destination_port": "443", "source_port": "1024:65535"}, ""},
+		"ports tcp/udp":             {map[string]interface{}{"protocol": "tcp/udp", "destination_port": "53"}, ""},
+		"destination port any":      {map[string]interface{}{"protocol": "icmp", "destination_port": "any"}, ""},
+		"destination port icmp":     {map[string]interface{}{"protocol": "icmp", "destination_port": "443"}, "destination_port"},
+		"source port any protocol":  {map[string]interface{}{"source_port": "1024"}, "source_port"},
+		"icmp type icmp":            {map[string]interface{}{"protocol": "icmp", "icmp_type": []interface{}{"echoreq"}}, ""},
+		"icmp type tcp":             {map[string]interface{}{"protocol": "tcp", "icmp_type": []interface{}{"echoreq"}}, "icmp_type"},
+		"synproxy tcp":              {map[string]interface{}{"protocol": "tcp", "state_type": "synproxy state"}, ""},
+		"synproxy udp":              {map[string]interface{}{"protocol": "udp", "state_type": "synproxy state"}, "state_type"},
+		"sloppy state udp":          {map[string]interface{}{"protocol": "udp", "state_type": "sloppy state"}, ""},
+		"tcp flags tcp":             {map[string]interface{}{"protocol": "tcp", "tcp_flag": []interface{}{map[string]interface{}{"flag": "syn", "present": true}, map[string]interface{}{"flag": "ack", "present": false}}}, ""},
+		"tcp flags udp":             {map[string]interface{}{"protocol": "udp", "tcp_flag": []interface{}{map[string]interface{}{"flag": "syn", "present": true}}}, "tcp_flag is only"},
+		"tcp flag repeated":         {map[string]interface{}{"protocol": "tcp", "tcp_flag": []interface{}{map[string]interface{}{"flag": "syn", "present": true}, map[string]interface{}{"flag": "syn", "present": false}}}, "more than once"},
+		"limiters":                  {map[string]interface{}{"dn_pipe": "in", "pdn_pipe": "out"}, ""},
+		"pdn pipe without dn pipe":  {map[string]interface{}{"pdn_pipe": "out"}, "pdn_pipe requires dn_pipe"},
+		"pdn pipe same as dn pipe":  {map[string]interface{}{"dn_pipe": "in", "pdn_pipe": "in"}, "pdn_pipe can't"},
+		"queues":                    {map[string]interface{}{"default_queue": "qDefault", "ack_queue": "qACK"}, ""},
+		"default queue":             {map[string]interface{}{"default_queue": "qDefault"}, ""},
+		"ack queue without default": {map[string]interface{}{"ack_queue": "qACK"}, "ack_queue requires default_queue"},
+		"ack queue same as default": {map[string]interface{}{"default_queue": "qACK", "ack_queue": "qACK"}, "ack_queue can't"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			err := planResource(resourceFirewallRule(), config)
+
+			if test.err == "" && err != nil {
+				t.Errorf("Expected %v to be valid but got %v", config, err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected %v to fail with %q but got %v", config, test.err, err)
+			}
+		})
+	}
+
+	err := planResource(resourceFirewallRule(), map[string]interface{}{"type": "block", "interface": []interface{}{"lan"}, "gateway": "WAN_DHCP", "quick": true})
+
+	if err == nil || !strings.Contains(err.Error(), "quick") || !strings.Contains(err.Error(), "gateway") {
+		t.Errorf("Expected errors for both quick and gateway but got %v", err)
+	}
+}
+
+func Test_firewallRuleScheduleMode(t *testing.T) {
+	tests := []struct {
+		ruleType string
+		schedule string
+		mode     string
+		valid    bool
+	}{
+		{"pass", "business_hours", scheduleModeActiveDuring, true},
+		{"block", "maintenance", scheduleModeBlockedDuring, true},
+		{"reject", "maintenance", scheduleModeBlockedDuring, true},
+		{"pass", "maintenance", scheduleModeBlockedDuring, false},
+		{"block", "business_hours", scheduleModeActiveDuring, false},
+		{"block", "", scheduleModeBlockedDuring, false},
+	}
+
+	for _, test := range tests {
+		config := map[string]interface{}{
+			"type":          test.ruleType,
+			"interface":     []interface{}{"lan"},
+			"schedule_mode": test.mode,
+		}
+
+		if test.schedule != "" {
+			config["schedule"] = test.schedule
+		}
+
+		err := planResource(resourceFirewallRule(), config)
+
+		if test.valid && err != nil {
+			t.Errorf("Expected %v to be valid but got %v", config, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("Expected %v to be invalid", config)
+		}
+	}
+}
+
+func firewallRulesResponse(count int) string {
+	rules := make([]string, count)
+
+	for i := range rules {
+		rules[i] = fmt.Sprintf(`{"tracker": "%d", "type": "pass", "interface": "lan"}`, i+1)
+	}
+
+	return "[" + strings.Join(rules, ",") + "]"
+}
+
+// readFirewallRules refreshes a rule resource per rule the way a plan would and returns how many times the rules were listed
+func readFirewallRules(t testing.TB, count int) int {
+	client, requests := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/rule": firewallRulesResponse(count),
+	})
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallRule().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_rule"]
+
+	for i := 1; i <= count; i++ {
+		d := res.TestResourceData()
+		d.SetId(fmt.Sprint(i))
+
+		if diags := res.ReadContext(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("Unable to read rule %d: %v", i, diags)
+		}
+	}
+
+	calls := 0
+
+	for _, request := range *requests {
+		if request == "GET /api/v1/firewall/rule" {
+			calls++
+		}
+	}
+
+	return calls
+}
+
+func Test_firewallRuleListIsCached(t *testing.T) {
+	if calls := readFirewallRules(t, 50); calls != 1 {
+		t.Errorf("Expected the rules to be listed once for all reads but they were listed %d times", calls)
+	}
+}
+
+func Test_firewallRuleCacheInvalidatedOnWrite(t *testing.T) {
+	client, requests := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/rule": firewallRulesResponse(1),
+		"PUT /api/v1/firewall/rule": `{"tracker": "1", "type": "block", "interface": "lan"}`,
+	})
+
+	r := resourceFirewallRule()
+
+	for _, step := range []func() error{
+		func() error { _, err := r.list(context.Background(), client, ""); return err },
+		func() error {
+			_, err := r.update(context.Background(), client, 1, &pfsenseapi.FirewallRuleRequest{Type: "block"})
+			return err
+		},
+		func() error { _, err := r.list(context.Background(), client, ""); return err },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+	}
+
+	expected := []string{"GET /api/v1/firewall/rule", "GET /api/v1/interface", "GET /api/v1/interface/group", "PUT /api/v1/firewall/rule", "GET /api/v1/firewall/rule"}
+
+	if !reflect.DeepEqual(*requests, expected) {
+		t.Errorf("Expected requests %v but got %v", expected, *requests)
+	}
+}
+
+func Benchmark_firewallRuleRefresh(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.ReportMetric(float64(readFirewallRules(b, 500)), "list-calls/refresh")
+	}
+}
+
+func Test_firewallRuleInterfaceExists(t *testing.T) {
+	client, requests := testAPIServer(t, map[string]string{
+		"GET /api/v1/interface":       `{"wan": {"if": "igb0", "descr": "WAN"}, "lan": {"if": "igb1", "descr": "LAN"}}`,
+		"GET /api/v1/interface/group": `[{"ifname": "INTERNAL", "members": "lan"}]`,
+		"POST /api/v1/firewall/rule":  `{"tracker": "1", "type": "pass", "interface": "lan"}`,
+	})
+
+	tests := map[string]struct {
+		interfaces []string
+		valid      bool
+	}{
+		"id":          {[]string{"lan"}, true},
+		"description": {[]string{"WAN"}, true},
+		"real id":     {[]string{"igb1"}, true},
+		"group":       {[]string{"INTERNAL"}, true},
+		"builtin":     {[]string{"openvpn"}, true},
+		"floating":    {[]string{"wan", "lan"}, true},
+		"missing":     {[]string{"opt5"}, false},
+		"one missing": {[]string{"wan", "opt5"}, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			*requests = nil
+
+			_, err := resourceFirewallRule().create(context.Background(), client, &pfsenseapi.FirewallRuleRequest{Type: "pass", Interface: test.interfaces})
+
+			if test.valid && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			} else if !test.valid {
+				if err == nil || !strings.Contains(err.Error(), "opt5 doesn't exist, valid interfaces are INTERNAL, LAN, WAN, igb0, igb1, ipsec, l2tp, lan, openvpn, pppoe, wan") {
+					t.Errorf("Expected an error listing the valid interfaces but got %v", err)
+				}
+
+				if slices.Contains(*requests, "POST /api/v1/firewall/rule") {
+					t.Errorf("Expected the rule not to be created")
+				}
+			}
+		})
+	}
+}
+
+func Test_firewallRuleInterfaceCache(t *testing.T) {
+	client, requests := testAPIServer(t, map[string]string{
+		"GET /api/v1/interface":      `{"wan": {"if": "igb0", "descr": "WAN"}, "lan": {"if": "igb1", "descr": "LAN"}}`,
+		"POST /api/v1/firewall/rule": `{"tracker": "1", "type": "pass", "interface": "lan"}`,
+	})
+
+	create := func() {
+		for i := 0; i < 3; i++ {
+			if _, err := resourceFirewallRule().create(context.Background(), client, &pfsenseapi.FirewallRuleRequest{Type: "pass", Interface: []string{"lan"}, Src: "WAN"}); err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+		}
+	}
+
+	listed := func() int {
+		count := 0
+
+		for _, request := range *requests {
+			if request == "GET /api/v1/interface" {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	create()
+
+	if count := listed(); count != 1 {
+		t.Errorf("Expected the interfaces to be listed once for every rule but they were listed %d times", count)
+	}
+
+	if err := resourceInterface().delete(context.Background(), client, "", "opt1"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	create()
+
+	if count := listed(); count != 2 {
+		t.Errorf("Expected the interfaces to be listed again after an interface was deleted but they were listed %d times", count)
+	}
+}
+
+func Test_firewallRuleUniqueDescriptions(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/rule": `[
+			{"tracker": "1700000001", "type": "pass", "interface": "lan", "descr": "Allow web traffic"},
+			{"tracker": "1700000002", "type": "pass", "interface": "wan,lan", "descr": "owner=ops"}
+		]`,
+	})
+
+	tests := map[string]struct {
+		config  map[string]interface{}
+		require bool
+		err     string
+	}{
+		"not required":          {map[string]interface{}{"description": "Allow web traffic"}, false, ""},
+		"unique":                {map[string]interface{}{"description": "Allow dns traffic"}, true, ""},
+		"other interface":       {map[string]interface{}{"description": "Allow web traffic", "interface": []interface{}{"wan"}}, true, ""},
+		"empty":                 {map[string]interface{}{}, true, "description or labels are required"},
+		"duplicate":             {map[string]interface{}{"description": "Allow web traffic"}, true, "Rule 1700000001 on interface lan"},
+		"duplicate labels":      {map[string]interface{}{"labels": map[string]interface{}{"owner": "ops"}}, true, "Rule 1700000002 on interface lan"},
+		"duplicate floating":    {map[string]interface{}{"description": "owner=ops", "floating": true, "interface": []interface{}{"opt1", "wan"}}, true, "Rule 1700000002 on interface wan"},
+		"empty without require": {map[string]interface{}{}, false, ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client.requireUniqueRuleDescriptions = test.require
+			config := map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			err := planResourceWithMeta(resourceFirewallRule(), config, client)
+
+			if test.err == "" && err != nil {
+				t.Errorf("Expected %v to be valid but got %v", config, err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected %v to fail with %q but got %v", config, test.err, err)
+			}
+		})
+	}
+}
+
+func Test_firewallRuleAliasTypes(t *testing.T) {
+	client, requests := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/alias": `[
+			{"name": "web_servers", "type": "host", "address": "10.0.0.10"},
+			{"name": "web_ports", "type": "port", "address": "80 443"}
+		]`,
+	})
+
+	tests := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"port alias on tcp":     {map[string]interface{}{"protocol": "tcp", "destination": "web_servers", "destination_port": "web_ports"}, ""},
+		"negated host alias":    {map[string]interface{}{"source": "!web_servers"}, ""},
+		"interface address":     {map[string]interface{}{"destination": "lanip"}, ""},
+		"port alias on icmp":    {map[string]interface{}{"protocol": "icmp", "destination_port": "web_ports"}, "destination_port is only available when protocol is tcp"},
+		"port alias as address": {map[string]interface{}{"destination": "web_ports"}, "destination refers to port alias web_ports"},
+		"host alias as port":    {map[string]interface{}{"protocol": "tcp", "source_port": "web_servers"}, "source_port refers to host alias web_servers"},
+		"alias not created yet": {map[string]interface{}{"destination": "mail_servers"}, ""},
+		"service port":          {map[string]interface{}{"protocol": "tcp", "destination_port": "https"}, ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			*requests = nil
+			config := map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			err := planResourceWithMeta(resourceFirewallRule(), config, client)
+
+			if test.err == "" && err != nil {
+				t.Errorf("Expected %v to be valid but got %v", config, err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected %v to fail with %q but got %v", config, test.err, err)
+			}
+		})
+	}
+
+	*requests = nil
+
+	if err := planResourceWithMeta(resourceFirewallRule(), map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}, "protocol": "tcp", "destination": "10.0.0.10", "destination_port": "443"}, client); err != nil || len(*requests) != 0 {
+		t.Errorf("Expected a rule without aliases not to list them but got %v and requests %v", err, *requests)
+	}
+
+	*requests = nil
+
+	for i := 0; i < 2; i++ {
+		if err := planResourceWithMeta(resourceFirewallRule(), map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}, "destination": "web_servers"}, client); err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+	}
+
+	if len(*requests) != 0 {
+		t.Errorf("Expected the aliases to be listed once while planning but got requests %v", *requests)
+	}
+
+	client.aliasCache.invalidate()
+
+	if err := planResourceWithMeta(resourceFirewallRule(), map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}, "destination": "web_servers"}, client); err != nil || len(*requests) != 1 {
+		t.Errorf("Expected the aliases to be listed again once the cache is invalidated but got %v and requests %v", err, *requests)
+	}
+}
+
+func Test_firewallRuleAddresses(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/alias": `[{"name": "web_servers", "type": "host", "address": "10.0.0.10"}]`,
+		"GET /api/v1/interface":      `{"lan": {"if": "igb1", "descr": "LAN"}, "opt2": {"if": "igb2", "descr": "DMZ"}}`,
+	})
+
+	tests := map[string]struct {
+		source      string
+		destination string
+		err         string
+	}{
+		"any":                   {"any", "any", ""},
+		"addresses":             {"10.0.0.0/24", "!10.0.1.10", ""},
+		"alias":                 {"any", "web_servers", ""},
+		"interface address":     {"lanip", "any", ""},
+		"interface description": {"DMZ", "any", ""},
+		"physical interface":    {"igb2ip", "any", ""},
+		"misspelt alias":        {"any", "web_sevrers", "destination refers to web_sevrers, which isn't an alias or interface, did you mean web_servers?"},
+		"misspelt alias case":   {"!Web_Server", "any", "did you mean web_servers?"},
+		"unknown alias":         {"any", "mail_servers", "destination refers to mail_servers, which isn't an alias or interface"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkRuleAddresses(context.Background(), client, &pfsenseapi.FirewallRuleRequest{Src: test.source, Dst: test.destination})
+
+			if test.err == "" && err != nil {
+				t.Errorf("Unexpected error %v", err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected %q but got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func Test_firewallRuleAliasCreatedInSameApply(t *testing.T) {
+	responses := map[string]string{
+		"GET /api/v1/firewall/alias": `[]`,
+		"GET /api/v1/interface":      `{"lan": {"if": "igb1", "descr": "LAN"}}`,
+		"POST /api/v1/firewall/rule": `{"tracker": "1", "type": "pass", "interface": "lan", "destination": {"address": "web_servers"}}`,
+	}
+	client, requests := testAPIServer(t, responses)
+	config := map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}, "destination": "web_servers"}
+
+	// The alias is only in the config, its name is known but it's not on pfSense yet
+	if err := planResourceWithMeta(resourceFirewallRule(), config, client); err != nil {
+		t.Fatalf("Expected the rule to plan before its alias exists but got %v", err)
+	}
+
+	// Creating the alias adds it and invalidates the cached aliases before the rule that depends on it is created
+	responses["GET /api/v1/firewall/alias"] = `[{"name": "web_servers", "type": "host", "address": "10.0.0.10"}]`
+	client.aliasCache.invalidate()
+
+	if _, err := resourceFirewallRule().create(context.Background(), client, &pfsenseapi.FirewallRuleRequest{Type: "pass", Interface: []string{"lan"}, Src: "any", Dst: "web_servers"}); err != nil {
+		t.Errorf("Expected the rule to be created once its alias exists but got %v", err)
+	}
+
+	if !slices.Contains(*requests, "POST /api/v1/firewall/rule") {
+		t.Errorf("Expected the rule to be created but got requests %v", *requests)
+	}
+}
+
+func Test_firewallRuleImportId(t *testing.T) {
+	client, _ := testAPIServer(t, map[string]string{
+		"GET /api/v1/firewall/rule": `[
+			{"tracker": "1700000001", "type": "pass", "interface": "lan", "descr": "Allow web traffic"},
+			{"tracker": "1700000002", "type": "block", "interface": "lan", "descr": "Allow web traffic"},
+			{"tracker": "1700000003", "type": "pass", "interface": "wan", "descr": "Allow ssh"}
+		]`,
+	})
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallRule().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_rule"]
+
+	tests := map[string]struct {
+		id       string
+		tracker  string
+		ruleType string
+		err      string
+	}{
+		"tracker":             {"1700000002", "1700000002", "block", ""},
+		"interface and id":    {"lan/1700000001", "1700000001", "pass", ""},
+		"wrong interface":     {"wan/1700000001", "", "", "Unable to find a rule with tracker 1700000001 on interface wan"},
+		"shared description":  {"Allow web traffic", "", "", "rules with the description \"Allow web traffic\" are lan/1700000001, lan/1700000002"},
+		"unique description":  {"Allow ssh", "", "", "rules with the description \"Allow ssh\" are wan/1700000003"},
+		"missing description": {"Allow dns", "", "", "no rule has the description"},
+		"missing tracker":     {"1700000009", "", "", "Unable to find item with Id 1700000009"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := res.TestResourceData()
+			d.SetId(test.id)
+
+			imported, err := res.Importer.StateContext(context.Background(), d, client)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("Expected error %q but got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if imported[0].Id() != test.tracker || imported[0].Get("type") != test.ruleType {
+				t.Errorf("Expected rule %s (%s) but got %s (%v)", test.tracker, test.ruleType, imported[0].Id(), imported[0].Get("type"))
+			}
+		})
+	}
+}
+
+func Test_firewallRulePortAnyNormalization(t *testing.T) {
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallRule().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_rule"]
+
+	tests := []struct {
+		oldValue string
+		newValue string
+		suppress bool
+	}{
+		{"any", "", true},
+		{"", "any", true},
+		{"ANY", "any", true},
+		{"", "", true},
+		{"any", "443", false},
+		{"", "443", false},
+		{"443", "8443", false},
+	}
+
+	for _, name := range []string{"source_port", "destination_port"} {
+		for _, test := range tests {
+			if suppress := res.Schema[name].DiffSuppressFunc(name, test.oldValue, test.newValue, nil); suppress != test.suppress {
+				t.Errorf("Expected %s change from %q to %q to be suppressed %v but was %v", name, test.oldValue, test.newValue, test.suppress, suppress)
+			}
+		}
+
+		err := planResource(resourceFirewallRule(), map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}, "protocol": "icmp", name: "22"})
+
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected %s on an icmp rule to be invalid but got %v", name, err)
+		}
+
+		err = planResource(resourceFirewallRule(), map[string]interface{}{"type": "pass", "interface": []interface{}{"lan"}, "protocol": "icmp", name: ""})
+
+		if err != nil {
+			t.Errorf("Expected an empty %s on an icmp rule to be valid but got %v", name, err)
+		}
+	}
+}
+
+func Test_firewallRuleEmptyStrings(t *testing.T) {
+	r := resourceFirewallRule()
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	r.AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_rule"]
+
+	set := &pfsenseapi.FirewallRule{
+		Tracker: 1, Type: "pass", Interface: "lan", Protocol: "tcp", Gateway: "WAN2GW", Sched: "work_hours",
+		AckQueue: "qACK", DefaultQueue: "qDefault", Dnpipe: "pipe1", PDNPipe: "pipe2",
+	}
+	cleared := &pfsenseapi.FirewallRule{Tracker: 1, Type: "pass", Interface: "lan", Protocol: "tcp"}
+	fields := []string{"gateway", "schedule", "ack_queue", "default_queue", "dn_pipe", "pdn_pipe"}
+
+	d := res.TestResourceData()
+	d.SetId("1")
+
+	if err := r.updateResource(d, set); err != nil {
+		t.Fatalf("Unable to read the rule: %v", err)
+	}
+
+	// The values are cleared in the UI, reading the rule must clear them in the state
+	if err := r.updateResource(d, cleared); err != nil {
+		t.Fatalf("Unable to read the rule: %v", err)
+	}
+
+	for _, field := range fields {
+		if value := d.Get(field); value != "" {
+			t.Errorf("Expected %s to be cleared but got %q", field, value)
+		}
+	}
+
+	for name, config := range map[string]map[string]interface{}{
+		"unset": {},
+		"empty": {"gateway": "", "schedule": "", "ack_queue": "", "default_queue": "", "dn_pipe": "", "pdn_pipe": ""},
+	} {
+		config["type"] = "pass"
+		config["interface"] = []interface{}{"lan"}
+		config["protocol"] = "tcp"
+
+		diff, err := res.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil)
+
+		if err != nil {
+			t.Fatalf("Unable to plan %s: %v", name, err)
+		}
+
+		for _, field := range fields {
+			if diff != nil && diff.Attributes[field] != nil {
+				t.Errorf("Expected no diff for %s when %s but got %v", field, name, diff.Attributes[field])
+			}
+		}
+	}
+}
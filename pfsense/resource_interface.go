@@ -2,32 +2,341 @@ package pfsense
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
-func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Interface, string] {
-	r := &resource[pfsenseapi.InterfaceRequest, pfsenseapi.Interface, string]{
+var interfaceStatusPollInterval = 2 * time.Second
+
+// isInterfaceUp is true when the interface status reports the physical interface as up
+func isInterfaceUp(ctx context.Context, client *providerClient, physical string) (bool, error) {
+	statuses, err := client.Status.ListInterfaceStatus(ctx)
+
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(statuses, func(status *pfsenseapi.InterfaceStatus) bool {
+		return status.If == physical && status.Status == "up"
+	}), nil
+}
+
+// waitForInterfaceUp polls the interface status until the interface reports up again, applying changes such as the
+// MTU can bounce the interface. It gives up when the context deadline (the resource timeout) is reached.
+func waitForInterfaceUp(ctx context.Context, client *providerClient, iface *pfsenseapi.Interface) error {
+	for {
+		if up, err := isInterfaceUp(ctx, client, iface.If); err != nil {
+			return err
+		} else if up {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Interface %s did not come back up: %w", iface.If, ctx.Err())
+		case <-time.After(interfaceStatusPollInterval):
+		}
+	}
+}
+
+// interfaceResponse is the interface configuration along with the IPv4 address it's been given by DHCP, which is only
+// in the interface status
+type interfaceResponse struct {
+	pfsenseapi.Interface
+	assignedAddress string
+}
+
+// isAssignedAddress is false for a missing address and for 0.0.0.0, which the status has while DHCP is still pending
+func isAssignedAddress(address string) bool {
+	ip := net.ParseIP(address)
+
+	return ip != nil && !ip.IsUnspecified()
+}
+
+// waitForInterfaceAddress polls the interface status until the interface has been given an IPv4 address. It gives up
+// when the context deadline (the resource timeout) is reached.
+func waitForInterfaceAddress(ctx context.Context, client *providerClient, iface *pfsenseapi.Interface) (string, error) {
+	for {
+		statuses, err := client.Status.ListInterfaceStatus(ctx)
+
+		if err != nil {
+			return "", err
+		}
+
+		for _, status := range statuses {
+			if status.If == iface.If && isAssignedAddress(status.Ipaddr) {
+				return status.Ipaddr, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("Interface %s was not given an address by DHCP: %w", iface.If, ctx.Err())
+		case <-time.After(interfaceStatusPollInterval):
+		}
+	}
+}
+
+// listInterfaces lists the interfaces, adding the assigned addresses of DHCP interfaces from the interface status
+func listInterfaces(ctx context.Context, client *providerClient) ([]*interfaceResponse, error) {
+	ifaces, err := client.Interface.ListInterfaces(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*interfaceResponse, len(ifaces))
+	dhcp := false
+
+	for i, iface := range ifaces {
+		responses[i] = &interfaceResponse{Interface: *iface}
+		dhcp = dhcp || iface.Ipaddr == "dhcp"
+	}
+
+	if !dhcp {
+		return responses, nil
+	}
+
+	statuses, err := client.Status.ListInterfaceStatus(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, response := range responses {
+		for _, status := range statuses {
+			if response.Ipaddr == "dhcp" && status.If == response.If && isAssignedAddress(status.Ipaddr) {
+				response.assignedAddress = status.Ipaddr
+			}
+		}
+	}
+
+	return responses, nil
+}
+
+// linkChanged is true when the request changes a setting that bounces the link when it's applied, a change to e.g. the
+// description leaves the link as it is
+func linkChanged(current *pfsenseapi.Interface, request *pfsenseapi.InterfaceRequest) bool {
+	mtuChanged := (current.Mtu.Value == nil) != (request.Mtu == nil) || (request.Mtu != nil && *current.Mtu.Value != *request.Mtu)
+
+	return mtuChanged || bool(current.Enable) != request.Enable || current.Media != request.Media ||
+		current.Mss != request.Mss || !strings.EqualFold(current.Spoofmac, request.Spoofmac)
+}
+
+// isUplinkInterface guesses whether the interface faces the internet from its settings, it's the WAN or gets its
+// address or a gateway from upstream
+func isUplinkInterface(d *schema.ResourceData) bool {
+	return d.Id() == "wan" || d.Get("type").(string) == "dhcp" || d.Get("gateway").(string) != "" ||
+		slices.Contains([]string{"dhcp6", "slaac", "6rd", "6to4"}, d.Get("type_v6").(string)) || d.Get("gateway_v6").(string) != ""
+}
+
+// macDiffSuppress treats MAC addresses that only differ in case or separator as the same, pfSense stores them as
+// entered but the interface uses the address regardless of how it's written.
+func macDiffSuppress(_, oldValue, newValue string, _ *schema.ResourceData) bool {
+	oldMac, oldErr := net.ParseMAC(oldValue)
+	newMac, newErr := net.ParseMAC(newValue)
+
+	return oldErr == nil && newErr == nil && oldMac.String() == newMac.String()
+}
+
+// interfaceV6Fields are the fields that only apply to each IPv6 configuration type along with whether they're required.
+var interfaceV6Fields = map[string]map[string]bool{
+	"staticv6": {"ip_address_v6": true, "subnet_v6": true, "gateway_v6": false},
+	"track6":   {"track_v6_interface": true, "track_v6_prefix_id_hex": false},
+	"6rd":      {"prefix_v6_rd": true, "gateway_6_rd": true, "prefix_6_rd_v4_plen": false},
+}
+
+// interfaceDhcpFields are the fields that only apply when the interface gets its IPv4 address with DHCP
+var interfaceDhcpFields = []string{
+	"adv_dhcp_config_advanced", "adv_dhcp_config_file_override", "adv_dhcp_config_file_override_file",
+	"adv_dhcp_option_modifiers", "adv_dhcp_pt_backoff_cutoff", "adv_dhcp_pt_initial_interval", "adv_dhcp_pt_reboot",
+	"adv_dhcp_pt_retry", "adv_dhcp_pt_select_timeout", "adv_dhcp_pt_timeout", "adv_dhcp_request_options",
+	"adv_dhcp_required_options", "adv_dhcp_send_options", "alias_address", "alias_subnet", "dhcp_cv_pt",
+	"dhcp_hostname", "dhcp_reject_from", "dhcp_vlan_enable",
+}
+
+func validateInterfaceDhcp(d *schema.ResourceDiff) error {
+	if ipType := d.Get("type").(string); !d.NewValueKnown("type") || ipType == "dhcp" {
+		return nil
+	}
+
+	for _, field := range interfaceDhcpFields {
+		if _, set := d.GetOk(field); set {
+			return fmt.Errorf("%s is only available when type is dhcp", field)
+		}
+	}
+
+	return nil
+}
+
+func validateInterfaceV6(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("type_v6") {
+		return nil
+	}
+
+	typeV6 := d.Get("type_v6").(string)
+
+	for fieldType, fields := range interfaceV6Fields {
+		for field, required := range fields {
+			_, set := d.GetOk(field)
+
+			if fieldType == typeV6 && required && !set && d.NewValueKnown(field) {
+				return fmt.Errorf("%s is required when type_v6 is %s", field, typeV6)
+			} else if fieldType != typeV6 && set {
+				return fmt.Errorf("%s is only available when type_v6 is %s", field, fieldType)
+			}
+		}
+	}
+
+	return nil
+}
+
+// interfaceNumbersV0 were strings before schema version 1, unset ones were stored as ""
+var interfaceNumbersV0 = []string{"mss", "track_v6_prefix_id_hex"}
+
+// interfaceStateTypeV0 is the type of the interface state before schema version 1
+func interfaceStateTypeV0(properties map[string]*resourceProperty[pfsenseapi.InterfaceRequest, interfaceResponse]) cty.Type {
+	attributes := map[string]*schema.Schema{}
+
+	for name, property := range properties {
+		attributes[name] = property.schema
+	}
+
+	for _, name := range interfaceNumbersV0 {
+		attribute := *attributes[name]
+		attribute.Type = schema.TypeString
+		attribute.ValidateFunc = nil
+		attributes[name] = &attribute
+	}
+
+	return (&schema.Resource{Schema: attributes}).CoreConfigSchema().ImpliedType()
+}
+
+// upgradeInterfaceStateV0 turns the strings stored for mss and track_v6_prefix_id_hex into numbers, "" becomes null
+func upgradeInterfaceStateV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	for _, name := range interfaceNumbersV0 {
+		value, ok := rawState[name].(string)
+
+		if !ok {
+			continue
+		}
+
+		if value == "" {
+			rawState[name] = nil
+			continue
+		}
+
+		i, err := strconv.Atoi(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("Unable to upgrade %s %q to a number: %v", name, value, err)
+		}
+
+		rawState[name] = i
+	}
+
+	return rawState, nil
+}
+
+func resourceInterface() *resource[pfsenseapi.InterfaceRequest, interfaceResponse, string] {
+	r := &resource[pfsenseapi.InterfaceRequest, interfaceResponse, string]{
 		name:        "pfsense_interface",
 		description: "Interface",
-		delete: func(ctx context.Context, client *pfsenseapi.Client, _ string, id string) error {
+		delete: func(ctx context.Context, client *providerClient, _ string, id string) error {
+			defer client.invalidateInterfaces()
 			return client.Interface.DeleteInterface(ctx, id)
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, _ string) ([]*pfsenseapi.Interface, error) {
-			return client.Interface.ListInterfaces(ctx)
+		list: func(ctx context.Context, client *providerClient, _ string) ([]*interfaceResponse, error) {
+			return listInterfaces(ctx, client)
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, id string, request *pfsenseapi.InterfaceRequest) (*pfsenseapi.Interface, error) {
-			request.Apply = true
-			return client.Interface.UpdateInterface(ctx, id, *request)
+		update: func(ctx context.Context, client *providerClient, id string, request *pfsenseapi.InterfaceRequest) (*interfaceResponse, error) {
+			request.Apply = client.autoReload
+			wait := false
+
+			// Only a change that bounces the link, or an interface that was up before, is waited for. A port without
+			// a carrier wouldn't come up for e.g. a new description.
+			if request.Apply && request.Enable {
+				ifaces, err := client.Interface.ListInterfaces(ctx)
+
+				if err != nil {
+					return nil, err
+				}
+
+				index := slices.IndexFunc(ifaces, func(iface *pfsenseapi.Interface) bool { return iface.Name == id })
+
+				if wait = index < 0 || linkChanged(ifaces[index], request); !wait {
+					if wait, err = isInterfaceUp(ctx, client, ifaces[index].If); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			defer client.invalidateInterfaces()
+			response, err := client.Interface.UpdateInterface(ctx, id, *request)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !wait {
+				return &interfaceResponse{Interface: *response}, nil
+			}
+
+			return &interfaceResponse{Interface: *response}, waitForInterfaceUp(ctx, client, response)
+		},
+		customizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			return errors.Join(validateInterfaceDhcp(d), validateInterfaceV6(d))
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.InterfaceRequest) (*pfsenseapi.Interface, error) {
-			request.Apply = true
-			return client.Interface.CreateInterface(ctx, *request)
+		warnings: func(d *schema.ResourceData) diag.Diagnostics {
+			var diags diag.Diagnostics
+
+			for _, name := range []string{"block_bogons", "block_private"} {
+				if d.Get(name).(bool) && !isUplinkInterface(d) {
+					diags = append(diags, diag.Diagnostic{
+						Severity: diag.Warning,
+						Summary:  fmt.Sprintf("%s is set on %s, which doesn't look like a WAN interface", name, d.Get("description")),
+						Detail:   "The rules it adds block traffic from those networks arriving on the interface, on an internal interface that's usually the traffic of its own hosts.",
+					})
+				}
+			}
+
+			return diags
+		},
+		timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.InterfaceRequest) (*interfaceResponse, error) {
+			request.Apply = client.autoReload
+			defer client.invalidateInterfaces()
+			response, err := client.Interface.CreateInterface(ctx, *request)
+
+			if err != nil {
+				return nil, err
+			}
+
+			// A DHCP address isn't assigned straight away, waiting for it means the address is known once the
+			// interface is created
+			if !request.Apply || !request.Enable || request.Type != "dhcp" {
+				return &interfaceResponse{Interface: *response}, nil
+			}
+
+			address, err := waitForInterfaceAddress(ctx, client, response)
+
+			return &interfaceResponse{Interface: *response, assignedAddress: address}, err
 		},
-		properties: map[string]*resourceProperty[pfsenseapi.InterfaceRequest, pfsenseapi.Interface]{
+		properties: map[string]*resourceProperty[pfsenseapi.InterfaceRequest, interfaceResponse]{
 			"adv_dhcp_config_advanced": {
 				schema: &schema.Schema{
 					Type:        schema.TypeBool,
@@ -38,7 +347,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpConfigAdvanced = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpConfigAdvanced, nil
 				},
 			},
@@ -52,7 +361,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpConfigFileOverride = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpConfigFileOverride, nil
 				},
 			},
@@ -66,7 +375,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpConfigFileOverrideFile = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpConfigFileOverrideFile, nil
 				},
 			},
@@ -80,7 +389,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpOptionModifiers = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpOptionModifiers, nil
 				},
 			},
@@ -92,10 +401,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					ValidateFunc: validation.IntAtLeast(1),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AdvDhcpPtBackoffCutoff = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AdvDhcpPtBackoffCutoff = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpPtBackoffCutoff, nil
 				},
 			},
@@ -107,10 +417,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					ValidateFunc: validation.IntAtLeast(1),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AdvDhcpPtInitialInterval = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AdvDhcpPtInitialInterval = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpPtInitialInterval, nil
 				},
 			},
@@ -122,10 +433,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					Description:  "Set the IPv4 DHCP protocol reboot interval. Must be numeric value greater than 1. This parameter is only available when `type` is set to `dhcp`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AdvDhcpPtReboot = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AdvDhcpPtReboot = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpPtReboot, nil
 				},
 			},
@@ -137,10 +449,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					Description:  "Set the IPv4 DHCP protocol retry interval. Must be numeric value greater than 1. This parameter is only available when `type` is set to `dhcp`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AdvDhcpPtRetry = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AdvDhcpPtRetry = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpPtRetry, nil
 				},
 			},
@@ -152,10 +465,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					Description:  "Set the IPv4 DHCP protocol select timeout interval. Must be numeric value greater than 0. This parameter is only available when `type` is set to `dhcp`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AdvDhcpPtSelectTimeout = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AdvDhcpPtSelectTimeout = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpPtSelectTimeout, nil
 				},
 			},
@@ -167,10 +481,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					Description:  "Set the IPv4 DHCP protocol timeout interval. Must be numeric value greater than 1. This parameter is only available when `type` is set to `dhcp`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AdvDhcpPtTimeout = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AdvDhcpPtTimeout = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpPtTimeout, nil
 				},
 			},
@@ -184,7 +499,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpRequestOptions = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpRequestOptions, nil
 				},
 			},
@@ -199,7 +514,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpRequiredOptions = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpRequiredOptions, nil
 				},
 			},
@@ -213,7 +528,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AdvDhcpSendOptions = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AdvDhcpSendOptions, nil
 				},
 			},
@@ -228,7 +543,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.AliasAddress = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AliasAddress, nil
 				},
 			},
@@ -240,10 +555,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					ValidateFunc: validation.IntBetween(1, 32),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.AliasSubnet = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.AliasSubnet = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.AliasSubnet, nil
 				},
 			},
@@ -251,13 +567,13 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 				schema: &schema.Schema{
 					Type:        schema.TypeBool,
 					Optional:    true,
-					Description: "Block bogon networks from routing over this interface.",
+					Description: "Block bogon networks from routing over this interface. This is meant for WAN interfaces, setting it on an interface that doesn't get its address or a gateway from upstream gives a warning.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
 					req.Blockbogons = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Blockbogons, nil
 				},
 			},
@@ -265,13 +581,13 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 				schema: &schema.Schema{
 					Type:        schema.TypeBool,
 					Optional:    true,
-					Description: "Block RFC1918 traffic from routing over this interface.",
+					Description: "Block RFC1918 traffic from routing over this interface. This is meant for WAN interfaces, setting it on an interface that doesn't get its address or a gateway from upstream gives a warning.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
 					req.Blockpriv = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Blockpriv, nil
 				},
 			},
@@ -285,7 +601,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Descr = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Descr, nil
 				},
 			},
@@ -297,10 +613,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					ValidateFunc: validation.IntBetween(0, 7),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.Dhcpcvpt = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.Dhcpcvpt = &value
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Dhcpcvpt, nil
 				},
 			},
@@ -315,7 +632,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Dhcphostname = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Dhcphostname, nil
 				},
 			},
@@ -339,7 +656,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Dhcprejectfrom = result
 					return nil
 				},
-				getFromResponse: func(res *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(res *interfaceResponse) (interface{}, error) {
 					return splitIntoArray(res.Dhcprejectfrom, ","), nil
 				},
 			},
@@ -353,7 +670,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Dhcpvlanenable = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Dhcpvlanenable, nil
 				},
 			},
@@ -368,7 +685,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Enable = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Enable, nil
 				},
 			},
@@ -382,7 +699,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Gateway = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Gateway, nil
 				},
 			},
@@ -391,13 +708,13 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					Type:         schema.TypeString,
 					Optional:     true,
 					Description:  "Set the 6RD interface IPv4 gateway address. This parameter is only required when `type6` is set to `6rd`",
-					ValidateFunc: validation.IsIPv6Address,
+					ValidateFunc: validation.IsIPv4Address,
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
 					req.Gateway6Rd = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Gateway6Rd, nil
 				},
 			},
@@ -411,7 +728,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Gatewayv6 = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Gatewayv6, nil
 				},
 			},
@@ -426,7 +743,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.If = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.If, nil
 				},
 			},
@@ -441,10 +758,25 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Ipaddr = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
+					// ipaddr holds dhcp rather than an address when the interface is configured by DHCP
+					if net.ParseIP(req.Ipaddr) == nil {
+						return nil, nil
+					}
+
 					return req.Ipaddr, nil
 				},
 			},
+			"assigned_ip_address": {
+				schema: &schema.Schema{
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "IPv4 address given to the interface by DHCP when `type` is `dhcp`. Creating a DHCP interface waits, up to the create timeout, until it has been given an address.",
+				},
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
+					return req.assignedAddress, nil
+				},
+			},
 			"ip_address_v6": {
 				schema: &schema.Schema{
 					Type:         schema.TypeString,
@@ -456,7 +788,12 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Ipaddrv6 = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
+					// ipaddrv6 holds the configuration type for anything other than a static address
+					if net.ParseIP(req.Ipaddrv6) == nil {
+						return nil, nil
+					}
+
 					return req.Ipaddrv6, nil
 				},
 			},
@@ -470,7 +807,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Ipv6Usev4Iface = d.Get(name).(bool)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Ipv6Usev4Iface, nil
 				},
 			},
@@ -484,37 +821,42 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Media = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Media, nil
 				},
 			},
 			"mss": {
 				schema: &schema.Schema{
-					Type:         schema.TypeString,
+					Type:         schema.TypeInt,
 					Optional:     true,
-					Description:  "MSS for this interface.",
+					Description:  "MSS clamping for TCP connections over this interface, in bytes.",
 					ValidateFunc: validation.IntBetween(576, 65535),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.Mss = d.Get(name).(string)
+					req.Mss = strconv.Itoa(d.Get(name).(int))
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
-					return req.Mss, nil
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
+					if req.Mss == "" {
+						return nil, nil
+					}
+
+					return strconv.Atoi(req.Mss)
 				},
 			},
 			"mtu": {
 				schema: &schema.Schema{
 					Type:         schema.TypeInt,
 					Optional:     true,
-					Description:  "MTU for this interface. If a VLAN interface, this value must be greater than parent.",
-					ValidateFunc: validation.IntBetween(1280, 8192),
+					Description:  "MTU for this interface. If a VLAN interface, this value must be greater than parent. Changing this may bounce the interface, the update waits for it to come back up.",
+					ValidateFunc: validation.IntBetween(1280, 9000),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.Mtu = d.Get(name).(*int)
+					i := d.Get(name).(int)
+					req.Mtu = &i
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Mtu, nil
 				},
 			},
@@ -528,7 +870,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Prefix6Rd = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Prefix6Rd, nil
 				},
 			},
@@ -540,10 +882,11 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					ValidateFunc: validation.IntBetween(0, 32),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.Prefix6RdV4Plen = d.Get(name).(*int)
+					i := d.Get(name).(int)
+					req.Prefix6RdV4Plen = &i
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Prefix6RdV4Plen, nil
 				},
 			},
@@ -552,15 +895,16 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					Type:         schema.TypeString,
 					Optional:     true,
 					ValidateFunc: validation.IsMACAddress,
-					Description:  "Custom MAC address to assign to the interface.",
+					Description:  "Custom MAC address to assign to the interface, leave unset to use the hardware address. Changing it bounces the interface, when `apply` and `enable` are set the update waits for it to come back up.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
 					req.Spoofmac = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Spoofmac, nil
 				},
+				diffSuppress: macDiffSuppress,
 			},
 			"subnet": {
 				schema: &schema.Schema{
@@ -574,7 +918,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Subnet = &i
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Subnet, nil
 				},
 			},
@@ -588,7 +932,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Subnetv6 = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Subnetv6, nil
 				},
 			},
@@ -602,21 +946,22 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Track6Interface = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Track6Interface, nil
 				},
 			},
 			"track_v6_prefix_id_hex": {
 				schema: &schema.Schema{
-					Type:        schema.TypeString,
+					Type:        schema.TypeInt,
 					Optional:    true,
-					Description: "Set the IPv6 prefix ID. The value in this field is the (Delegated) IPv6 prefix ID. This determines the configurable network ID based on the dynamic IPv6 connection. The default value is 0. This parameter is only available when `type6` is set to",
+					Description: "Set the IPv6 prefix ID. The value in this field is the (Delegated) IPv6 prefix ID. This determines the configurable network ID based on the dynamic IPv6 connection. The default value is 0. This parameter is only available when `type6` is set to `track6`.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
-					req.Track6PrefixIdHex = d.Get(name).(*int)
+					i := d.Get(name).(int)
+					req.Track6PrefixIdHex = &i
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					return req.Track6PrefixIdHex, nil
 				},
 			},
@@ -631,7 +976,7 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 					req.Type = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
 					if req.Ipaddr == "dhcp" {
 						return req.Ipaddr, nil
 					} else if req.Ipaddr != "" {
@@ -645,21 +990,27 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 				schema: &schema.Schema{
 					Type:         schema.TypeString,
 					Optional:     true,
-					Description:  "IPv6 configuration type.",
+					Description:  "IPv6 configuration type. `staticv6` requires `ip_address_v6` and `subnet_v6`, `track6` requires `track_v6_interface` and `6rd` requires `prefix_v6_rd` and `gateway_6_rd`.",
 					ValidateFunc: validation.StringInSlice([]string{"staticv6", "dhcp6", "slaac", "6rd", "track6", "6to4"}, false),
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.InterfaceRequest) error {
 					req.Type6 = d.Get(name).(string)
 					return nil
 				},
-				getFromResponse: func(req *pfsenseapi.Interface) (interface{}, error) {
+				getFromResponse: func(req *interfaceResponse) (interface{}, error) {
+					if net.ParseIP(req.Ipaddrv6) != nil {
+						return "staticv6", nil
+					} else if req.Ipaddrv6 != "" {
+						return req.Ipaddrv6, nil
+					}
+
 					return req.Type6, nil
 				},
 			},
 		},
 	}
 
-	r.getId = func(ctx context.Context, client *pfsenseapi.Client, i *pfsenseapi.Interface) (string, error) {
+	r.getId = func(ctx context.Context, client *providerClient, i *interfaceResponse) (string, error) {
 		ifaces, err := r.list(ctx, client, "")
 
 		if err != nil {
@@ -675,5 +1026,12 @@ func resourceInterface() *resource[pfsenseapi.InterfaceRequest, pfsenseapi.Inter
 		return "", fmt.Errorf("Unable to find interface with If %s after creation", i.If)
 	}
 
+	r.schemaVersion = 1
+	r.stateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    interfaceStateTypeV0(r.properties),
+		Upgrade: upgradeInterfaceStateV0,
+	}}
+
 	return r
 }
@@ -0,0 +1,121 @@
+package pfsense
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// findInterface returns the interface with the name, e.g. wan or opt1, or nil if there isn't one
+func findInterface(ctx context.Context, client *providerClient, name string) (*interfaceResponse, error) {
+	ifaces, err := listInterfaces(ctx, client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range ifaces {
+		if iface.Name == name {
+			return iface, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// setInterfaceEnabled writes the interface back with only enable changed
+func setInterfaceEnabled(ctx context.Context, client *providerClient, name string, enable bool) diag.Diagnostics {
+	iface, err := findInterface(ctx, client, name)
+
+	if err != nil {
+		return diag.FromErr(err)
+	} else if iface == nil {
+		return diag.Errorf("Interface %s doesn't exist", name)
+	}
+
+	r := resourceInterface()
+	request, err := r.requestFromResponse(iface)
+
+	if err != nil {
+		return diag.Errorf("Unable to read interface %s: %v", name, err)
+	}
+
+	request.Enable = enable
+
+	if client.dryRun {
+		return r.dryRunDiagnostics("updated", name, request)
+	}
+
+	if _, err := r.update(ctx, client, name, request); err != nil {
+		return diag.Errorf("Unable to update interface %s: %v", name, err)
+	}
+
+	return nil
+}
+
+func resourceInterfaceState() *schema.Resource {
+	return &schema.Resource{
+		Description: "Enables or disables an interface without managing the rest of its configuration, which is written back as it is. " +
+			"The interface is left in its current state when this is destroyed. Don't use this with a `pfsense_interface` for the same interface, they'll both try to manage `enable`.",
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			name := d.Get("interface").(string)
+
+			if diags := setInterfaceEnabled(ctx, m.(*providerClient), name, d.Get("enable").(bool)); diags.HasError() {
+				return diags
+			}
+
+			d.SetId(name)
+
+			return nil
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			iface, err := findInterface(ctx, m.(*providerClient), d.Id())
+
+			if err != nil {
+				return diag.FromErr(err)
+			} else if iface == nil {
+				d.SetId("")
+				return nil
+			}
+
+			if err := d.Set("interface", iface.Name); err != nil {
+				return diag.FromErr(err)
+			}
+
+			if err := d.Set("enable", bool(iface.Enable)); err != nil {
+				return diag.FromErr(err)
+			}
+
+			return nil
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return setInterfaceEnabled(ctx, m.(*providerClient), d.Id(), d.Get("enable").(bool))
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			d.SetId("")
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"interface": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the interface, e.g. `wan` or `opt1`, as used for the ID of `pfsense_interface`.",
+			},
+			"enable": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the interface is enabled.",
+			},
+		},
+	}
+}
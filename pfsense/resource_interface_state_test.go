@@ -0,0 +1,87 @@
+package pfsense
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+func resourceInterfaceStateTest() resourceTest {
+	return &schemaResourceTest{
+		name:     "pfsense_interface_state",
+		resource: resourceInterfaceState(),
+	}
+}
+
+func Test_interfaceState(t *testing.T) {
+	iface := `{"if":"igb1","descr":"DMZ","enable":"","ipaddr":"10.0.5.1","subnet":"24","mtu":"9000","spoofmac":"00:11:22:33:44:55"}`
+	updates := []map[string]interface{}{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := `{"opt1":` + iface + `}`
+
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			update := map[string]interface{}{}
+
+			if err := json.Unmarshal(body, &update); err != nil {
+				t.Errorf("Invalid update %v", err)
+			}
+
+			updates = append(updates, update)
+
+			// The interface is then listed without enable, as pfSense leaves it out of disabled interfaces
+			iface = strings.Replace(iface, `"enable":"",`, "", 1)
+			data = iface
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + data + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false)
+	r := resourceInterfaceState()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"interface": "opt2", "enable": false})
+
+	if diags := r.CreateContext(context.Background(), d, client); !diags.HasError() {
+		t.Errorf("Expected an error for an interface that doesn't exist")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"interface": "opt1", "enable": false})
+
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	if d.Id() != "opt1" {
+		t.Errorf("Expected ID opt1 but got %q", d.Id())
+	}
+
+	if len(updates) != 1 {
+		t.Fatalf("Expected one update but got %v", updates)
+	}
+
+	expected := map[string]interface{}{"id": "opt1", "enable": false, "descr": "DMZ", "ipaddr": "10.0.5.1", "mtu": float64(9000), "spoofmac": "00:11:22:33:44:55"}
+
+	for name, value := range expected {
+		if updates[0][name] != value {
+			t.Errorf("Expected %s to be %v but got %v", name, value, updates[0][name])
+		}
+	}
+
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	if d.Get("enable").(bool) {
+		t.Errorf("Expected the interface to be read as disabled")
+	}
+}
@@ -1,11 +1,443 @@
 package pfsense
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
 func resourceInterfaceTest() resourceTest {
-	return &tfResourceTest[pfsenseapi.InterfaceRequest, pfsenseapi.Interface, string]{
+	return &tfResourceTest[pfsenseapi.InterfaceRequest, interfaceResponse, string]{
 		resource: resourceInterface(),
 	}
 }
+
+func interfaceStatusServer(t *testing.T, statuses ...string) *providerClient {
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":[{"name":"opt1","if":"igb1","status":"` + status + `"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false)
+}
+
+func Test_waitForInterfaceUp(t *testing.T) {
+	defer func(interval time.Duration) { interfaceStatusPollInterval = interval }(interfaceStatusPollInterval)
+	interfaceStatusPollInterval = time.Millisecond
+
+	client := interfaceStatusServer(t, "down", "no carrier", "up")
+
+	if err := waitForInterfaceUp(context.Background(), client, &pfsenseapi.Interface{If: "igb1"}); err != nil {
+		t.Errorf("Expected interface to come up but got %v", err)
+	}
+}
+
+func Test_waitForInterfaceUpRespectsTimeout(t *testing.T) {
+	defer func(interval time.Duration) { interfaceStatusPollInterval = interval }(interfaceStatusPollInterval)
+	interfaceStatusPollInterval = time.Millisecond
+
+	client := interfaceStatusServer(t, "down")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := waitForInterfaceUp(ctx, client, &pfsenseapi.Interface{If: "igb1"}); err == nil {
+		t.Errorf("Expected an error when the interface doesn't come up before the timeout")
+	}
+}
+
+func Test_interfaceUpdateWaitsForLinkChanges(t *testing.T) {
+	defer func(interval time.Duration) { interfaceStatusPollInterval = interval }(interfaceStatusPollInterval)
+	interfaceStatusPollInterval = time.Millisecond
+
+	mtu, largerMtu := 1500, 9000
+
+	tests := map[string]struct {
+		statuses []string
+		request  pfsenseapi.InterfaceRequest
+		polls    int
+	}{
+		"description without carrier": {[]string{"no carrier"}, pfsenseapi.InterfaceRequest{Descr: "DMZ2", Enable: true, Mtu: &mtu}, 1},
+		"description while up":        {[]string{"up"}, pfsenseapi.InterfaceRequest{Descr: "DMZ2", Enable: true, Mtu: &mtu}, 2},
+		"mtu":                         {[]string{"down", "down", "up"}, pfsenseapi.InterfaceRequest{Descr: "DMZ", Enable: true, Mtu: &largerMtu}, 3},
+		"spoofed mac":                 {[]string{"down", "up"}, pfsenseapi.InterfaceRequest{Descr: "DMZ", Enable: true, Mtu: &mtu, Spoofmac: "00:11:22:33:44:55"}, 2},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			polls := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data := `{"if": "igb1", "descr": "DMZ", "enable": "", "mtu": 1500}`
+
+				switch r.Method + " " + r.URL.Path {
+				case "GET /api/v1/interface":
+					data = `{"opt1": ` + data + `}`
+				case "GET /api/v1/status/interface":
+					data = `[{"name": "opt1", "if": "igb1", "status": "` + test.statuses[min(polls, len(test.statuses)-1)] + `"}]`
+					polls++
+				}
+
+				_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + data + `}`))
+			}))
+			t.Cleanup(server.Close)
+
+			client := newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), true)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if _, err := resourceInterface().update(ctx, client, "opt1", &test.request); err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if polls != test.polls {
+				t.Errorf("Expected the status to be polled %d times but it was polled %d times", test.polls, polls)
+			}
+		})
+	}
+}
+
+func Test_interfaceV6Types(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"staticv6":               {map[string]interface{}{"type_v6": "staticv6", "ip_address_v6": "2001:db8::1", "subnet_v6": "64"}, ""},
+		"staticv6 without ip":    {map[string]interface{}{"type_v6": "staticv6", "subnet_v6": "64"}, "ip_address_v6 is required"},
+		"staticv6 without sub":   {map[string]interface{}{"type_v6": "staticv6", "ip_address_v6": "2001:db8::1"}, "subnet_v6 is required"},
+		"dhcp6":                  {map[string]interface{}{"type_v6": "dhcp6"}, ""},
+		"dhcp6 with ip":          {map[string]interface{}{"type_v6": "dhcp6", "ip_address_v6": "2001:db8::1"}, "ip_address_v6 is only available when type_v6 is staticv6"},
+		"slaac":                  {map[string]interface{}{"type_v6": "slaac"}, ""},
+		"6to4":                   {map[string]interface{}{"type_v6": "6to4"}, ""},
+		"track6":                 {map[string]interface{}{"type_v6": "track6", "track_v6_interface": "wan", "track_v6_prefix_id_hex": 1}, ""},
+		"track6 without iface":   {map[string]interface{}{"type_v6": "track6"}, "track_v6_interface is required"},
+		"6rd":                    {map[string]interface{}{"type_v6": "6rd", "prefix_v6_rd": "2001:db8::/32", "gateway_6_rd": "192.0.2.1", "prefix_6_rd_v4_plen": 8}, ""},
+		"6rd without gateway":    {map[string]interface{}{"type_v6": "6rd", "prefix_v6_rd": "2001:db8::/32"}, "gateway_6_rd is required"},
+		"6rd gateway not ipv4":   {map[string]interface{}{"type_v6": "6rd", "prefix_v6_rd": "2001:db8::/32", "gateway_6_rd": "2001:db8::1"}, "gateway_6_rd"},
+		"no type with 6rd field": {map[string]interface{}{"prefix_v6_rd": "2001:db8::/32"}, "prefix_v6_rd is only available when type_v6 is 6rd"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"if": "igb1", "description": "OPT1"}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			err := planResource(resourceInterface(), config)
+
+			if test.err == "" && err != nil {
+				t.Errorf("Expected %v to be valid but got %v", config, err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected %v to fail with %q but got %v", config, test.err, err)
+			}
+		})
+	}
+}
+
+func Test_interfaceV6TypeRoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		response interfaceResponse
+		typeV6   string
+		address  string
+	}{
+		"static": {interfaceResponse{Interface: pfsenseapi.Interface{If: "igb1", Descr: "OPT1", Ipaddrv6: "2001:db8::1", Subnetv6: "64"}}, "staticv6", "2001:db8::1"},
+		"dhcp6":  {interfaceResponse{Interface: pfsenseapi.Interface{If: "igb1", Descr: "OPT1", Ipaddrv6: "dhcp6"}}, "dhcp6", ""},
+		"track6": {interfaceResponse{Interface: pfsenseapi.Interface{If: "igb1", Descr: "OPT1", Ipaddrv6: "track6", Track6Interface: "wan"}}, "track6", ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, request := importRoundTrip(t, resourceInterface(), &test.response)
+
+			if config["type_v6"] != test.typeV6 {
+				t.Errorf("Expected type_v6 %s but got %v", test.typeV6, config["type_v6"])
+			}
+
+			if request.Ipaddrv6 != test.address {
+				t.Errorf("Expected ip_address_v6 %q but got %q", test.address, request.Ipaddrv6)
+			}
+		})
+	}
+}
+
+func Test_macDiffSuppress(t *testing.T) {
+	tests := []struct {
+		oldValue string
+		newValue string
+		suppress bool
+	}{
+		{"00:1a:2b:3c:4d:5e", "00:1A:2B:3C:4D:5E", true},
+		{"00:1a:2b:3c:4d:5e", "00-1a-2b-3c-4d-5e", true},
+		{"00:1a:2b:3c:4d:5e", "00:1a:2b:3c:4d:5f", false},
+		{"", "00:1a:2b:3c:4d:5e", false},
+		{"00:1a:2b:3c:4d:5e", "", false},
+	}
+
+	for _, test := range tests {
+		if actual := macDiffSuppress("spoof_mac", test.oldValue, test.newValue, nil); actual != test.suppress {
+			t.Errorf("Expected suppressing %q to %q to be %v", test.oldValue, test.newValue, test.suppress)
+		}
+	}
+}
+
+func Test_interfaceSpoofMacRoundTrip(t *testing.T) {
+	for _, mac := range []string{"00:1a:2b:3c:4d:5e", ""} {
+		config, request := importRoundTrip(t, resourceInterface(), &interfaceResponse{Interface: pfsenseapi.Interface{If: "igb0", Descr: "WAN", Spoofmac: mac}})
+
+		if _, set := config["spoof_mac"]; set != (mac != "") || request.Spoofmac != mac {
+			t.Errorf("Expected spoof_mac %q to round trip but got config %v and request %q", mac, config, request.Spoofmac)
+		}
+	}
+}
+
+func Test_interfaceDhcpFields(t *testing.T) {
+	tests := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"dhcp":             {map[string]interface{}{"type": "dhcp", "dhcp_hostname": "edge.example.com", "dhcp_reject_from": []interface{}{"192.168.100.1"}, "adv_dhcp_pt_timeout": 60}, ""},
+		"static":           {map[string]interface{}{"type": "staticv4", "ip_address": "10.0.0.1", "subnet": 24}, ""},
+		"static hostname":  {map[string]interface{}{"type": "staticv4", "dhcp_hostname": "edge.example.com"}, "dhcp_hostname is only available when type is dhcp"},
+		"static modifiers": {map[string]interface{}{"type": "staticv4", "adv_dhcp_option_modifiers": "supersede domain-name-servers 1.1.1.1"}, "adv_dhcp_option_modifiers"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"if": "igb0", "description": "WAN"}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			err := planResource(resourceInterface(), config)
+
+			if test.err == "" && err != nil {
+				t.Errorf("Expected %v to be valid but got %v", config, err)
+			} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expected %v to fail with %q but got %v", config, test.err, err)
+			}
+		})
+	}
+}
+
+func Test_interfaceDhcpRoundTrip(t *testing.T) {
+	timeout := 60
+	response := &interfaceResponse{Interface: pfsenseapi.Interface{
+		If:                     "igb0",
+		Descr:                  "WAN",
+		Ipaddr:                 "dhcp",
+		Dhcphostname:           "edge.example.com",
+		Dhcprejectfrom:         "192.168.100.1",
+		AdvDhcpPtTimeout:       pfsenseapi.OptionalJSONInt{Value: &timeout},
+		AdvDhcpOptionModifiers: "supersede domain-name-servers 1.1.1.1",
+	}}
+
+	config, request := importRoundTrip(t, resourceInterface(), response)
+
+	if request.Type != "dhcp" || request.Dhcphostname != "edge.example.com" || request.AdvDhcpOptionModifiers != response.AdvDhcpOptionModifiers {
+		t.Errorf("Generated config %v did not round trip, got %+v", config, request)
+	}
+
+	if request.AdvDhcpPtTimeout == nil || *request.AdvDhcpPtTimeout != 60 {
+		t.Errorf("Expected adv_dhcp_pt_timeout 60 but got %v", request.AdvDhcpPtTimeout)
+	}
+
+	if !reflect.DeepEqual(request.Dhcprejectfrom, []string{"192.168.100.1"}) {
+		t.Errorf("Expected dhcp_reject_from to round trip but got %v", request.Dhcprejectfrom)
+	}
+}
+
+func Test_interfaceCreateWaitsForDhcpAddress(t *testing.T) {
+	defer func(interval time.Duration) { interfaceStatusPollInterval = interval }(interfaceStatusPollInterval)
+	interfaceStatusPollInterval = time.Millisecond
+
+	addresses := []string{"", "", "0.0.0.0", "203.0.113.7"}
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data string
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/interface":
+			data = `{"if": "igb0", "descr": "WAN", "enable": true, "ipaddr": "dhcp"}`
+		case "GET /api/v1/interface":
+			data = `{"wan": {"if": "igb0", "descr": "WAN", "enable": true, "ipaddr": "dhcp"}}`
+		case "GET /api/v1/status/interface":
+			data = `[{"name": "wan", "if": "igb0", "status": "up", "ipaddr": "` + addresses[min(polls, len(addresses)-1)] + `"}]`
+			polls++
+		default:
+			data = "null"
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + data + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), true)
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceInterface().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_interface"]
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"if": "igb0", "description": "WAN", "enable": true, "type": "dhcp"})
+
+	if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	if address := d.Get("assigned_ip_address"); address != "203.0.113.7" {
+		t.Errorf("Expected the DHCP address but got %q after %d polls", address, polls)
+	}
+
+	if polls < len(addresses) {
+		t.Errorf("Expected the status to be polled until the address was assigned but it was polled %d times", polls)
+	}
+}
+
+func Test_interfaceCreateDhcpTimeoutKeepsId(t *testing.T) {
+	defer func(interval time.Duration) { interfaceStatusPollInterval = interval }(interfaceStatusPollInterval)
+	interfaceStatusPollInterval = time.Millisecond
+
+	client, _ := testAPIServer(t, map[string]string{
+		"POST /api/v1/interface":       `{"if": "igb0", "descr": "WAN", "enable": true, "ipaddr": "dhcp"}`,
+		"GET /api/v1/interface":        `{"wan": {"if": "igb0", "descr": "WAN", "enable": true, "ipaddr": "dhcp"}}`,
+		"GET /api/v1/status/interface": `[{"name": "wan", "if": "igb0", "status": "up", "ipaddr": "0.0.0.0"}]`,
+	})
+	client.autoReload = true
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceInterface().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_interface"]
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"if": "igb0", "description": "WAN", "enable": true, "type": "dhcp"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if diags := res.CreateContext(ctx, d, client); !diags.HasError() {
+		t.Errorf("Expected the create to time out waiting for the address but got %v", diags)
+	}
+
+	if d.Id() != "wan" {
+		t.Errorf("Expected the interface that was created to keep its ID so it's tainted but got %q", d.Id())
+	}
+}
+
+func Test_interfaceBlockNetworksWarning(t *testing.T) {
+	tests := map[string]struct {
+		id       string
+		config   map[string]interface{}
+		warnings int
+	}{
+		"wan":             {"wan", map[string]interface{}{"type": "staticv4", "block_private": true, "block_bogons": true}, 0},
+		"dhcp":            {"opt1", map[string]interface{}{"type": "dhcp", "block_private": true, "block_bogons": true}, 0},
+		"static gateway":  {"opt1", map[string]interface{}{"type": "staticv4", "gateway": "WAN2GW", "block_bogons": true}, 0},
+		"slaac":           {"opt1", map[string]interface{}{"type_v6": "slaac", "block_bogons": true}, 0},
+		"lan":             {"lan", map[string]interface{}{"type": "staticv4", "block_private": true, "block_bogons": true}, 2},
+		"lan bogons only": {"lan", map[string]interface{}{"type": "staticv4", "block_bogons": true}, 1},
+		"lan unset":       {"lan", map[string]interface{}{"type": "staticv4"}, 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := resourceInterface()
+			provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+			r.AddResource(provider)
+
+			config := map[string]interface{}{"if": "igb0", "description": "NET"}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			d := schema.TestResourceDataRaw(t, provider.ResourcesMap["pfsense_interface"].Schema, config)
+			d.SetId(test.id)
+
+			if diags := r.getWarnings(d); len(diags) != test.warnings {
+				t.Errorf("Expected %d warnings but got %v", test.warnings, diags)
+			}
+		})
+	}
+
+	config, request := importRoundTrip(t, resourceInterface(), &interfaceResponse{Interface: pfsenseapi.Interface{If: "igb0", Descr: "WAN", Ipaddr: "dhcp", Blockpriv: true, Blockbogons: true}})
+
+	if !request.Blockpriv || !request.Blockbogons {
+		t.Errorf("Expected block_private and block_bogons to round trip but got %v", config)
+	}
+}
+
+func Test_interfaceStateUpgradeV0(t *testing.T) {
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceInterface().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_interface"]
+
+	tests := map[string]struct {
+		state    string
+		mss      cty.Value
+		prefixId cty.Value
+	}{
+		"unset":  {`{"id": "lan", "if": "igb1", "mtu": 1500, "mss": "", "track_v6_prefix_id_hex": ""}`, cty.NullVal(cty.Number), cty.NullVal(cty.Number)},
+		"set":    {`{"id": "lan", "if": "igb1", "mss": "1400", "track_v6_prefix_id_hex": "1"}`, cty.NumberIntVal(1400), cty.NumberIntVal(1)},
+		"absent": {`{"id": "lan", "if": "igb1"}`, cty.NullVal(cty.Number), cty.NullVal(cty.Number)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ctyjson.Unmarshal([]byte(test.state), res.StateUpgraders[0].Type); err != nil {
+				t.Fatalf("Expected the old state to match the version 0 schema: %v", err)
+			}
+
+			var rawState map[string]interface{}
+
+			if err := json.Unmarshal([]byte(test.state), &rawState); err != nil {
+				t.Fatalf("Unable to parse state: %v", err)
+			}
+
+			upgraded, err := res.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			data, err := json.Marshal(upgraded)
+
+			if err != nil {
+				t.Fatalf("Unable to write state: %v", err)
+			}
+
+			state, err := ctyjson.Unmarshal(data, res.CoreConfigSchema().ImpliedType())
+
+			if err != nil {
+				t.Fatalf("Expected the upgraded state to match the current schema: %v", err)
+			}
+
+			if mss := state.GetAttr("mss"); !mss.RawEquals(test.mss) {
+				t.Errorf("Expected mss %#v but got %#v", test.mss, mss)
+			}
+
+			if prefixId := state.GetAttr("track_v6_prefix_id_hex"); !prefixId.RawEquals(test.prefixId) {
+				t.Errorf("Expected track_v6_prefix_id_hex %#v but got %#v", test.prefixId, prefixId)
+			}
+		})
+	}
+
+	if _, err := res.StateUpgraders[0].Upgrade(context.Background(), map[string]interface{}{"mss": "auto"}, nil); err == nil {
+		t.Errorf("Expected an error for an mss that isn't a number")
+	}
+}
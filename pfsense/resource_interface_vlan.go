@@ -12,19 +12,19 @@ func resourceInterfaceVLAN() *resource[pfsenseapi.VLANRequest, pfsenseapi.VLAN,
 	return &resource[pfsenseapi.VLANRequest, pfsenseapi.VLAN, string]{
 		name:        "pfsense_interface_vlan",
 		description: "VLAN",
-		delete: func(ctx context.Context, client *pfsenseapi.Client, _ string, id string) error {
+		delete: func(ctx context.Context, client *providerClient, _ string, id string) error {
 			return client.Interface.DeleteVLAN(ctx, id)
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, _ string) ([]*pfsenseapi.VLAN, error) {
+		list: func(ctx context.Context, client *providerClient, _ string) ([]*pfsenseapi.VLAN, error) {
 			return client.Interface.ListVLANs(ctx)
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, id string, request *pfsenseapi.VLANRequest) (*pfsenseapi.VLAN, error) {
+		update: func(ctx context.Context, client *providerClient, id string, request *pfsenseapi.VLANRequest) (*pfsenseapi.VLAN, error) {
 			return client.Interface.UpdateVLAN(ctx, id, *request)
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.VLANRequest) (*pfsenseapi.VLAN, error) {
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.VLANRequest) (*pfsenseapi.VLAN, error) {
 			return client.Interface.CreateVLAN(ctx, *request)
 		},
-		getId: func(_ context.Context, _ *pfsenseapi.Client, response *pfsenseapi.VLAN) (string, error) {
+		getId: func(_ context.Context, _ *providerClient, response *pfsenseapi.VLAN) (string, error) {
 			return response.Vlanif, nil
 		},
 		properties: map[string]*resourceProperty[pfsenseapi.VLANRequest, pfsenseapi.VLAN]{
@@ -62,15 +62,22 @@ func resourceInterfaceVLAN() *resource[pfsenseapi.VLANRequest, pfsenseapi.VLAN,
 				schema: &schema.Schema{
 					Type:         schema.TypeInt,
 					Optional:     true,
+					Default:      0,
 					ValidateFunc: validation.IntBetween(0, 7),
-					Description:  "802.1q VLAN priority.",
+					Description:  "802.1q VLAN priority, from 0 to 7. A VLAN without a priority is read as 0.",
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.VLANRequest) error {
-					req.Pcp = d.Get(name).(*int)
+					value := d.Get(name).(int)
+					req.Pcp = &value
 					return nil
 				},
 				getFromResponse: func(req *pfsenseapi.VLAN) (interface{}, error) {
-					return req.Pcp, nil
+					// pfSense stores an empty pcp when no priority has been set, which is the same as priority 0
+					if req.Pcp.Value == nil {
+						return 0, nil
+					}
+
+					return *req.Pcp.Value, nil
 				},
 			},
 			"description": {
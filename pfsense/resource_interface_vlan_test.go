@@ -1,6 +1,11 @@
 package pfsense
 
 import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
@@ -9,3 +14,60 @@ func resourceInterfaceVLANTest() resourceTest {
 		resource: resourceInterfaceVLAN(),
 	}
 }
+
+func Test_interfaceVLANPcp(t *testing.T) {
+	zero, five := 0, 5
+
+	tests := map[string]struct {
+		pcp    pfsenseapi.OptionalJSONInt
+		config map[string]interface{}
+	}{
+		"stored empty, unset": {pfsenseapi.OptionalJSONInt{}, map[string]interface{}{}},
+		"stored empty, zero":  {pfsenseapi.OptionalJSONInt{}, map[string]interface{}{"pcp": 0}},
+		"stored zero, unset":  {pfsenseapi.OptionalJSONInt{Value: &zero}, map[string]interface{}{}},
+		"stored five, five":   {pfsenseapi.OptionalJSONInt{Value: &five}, map[string]interface{}{"pcp": 5}},
+	}
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceInterfaceVLAN().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_interface_vlan"]
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := &pfsenseapi.VLAN{If: "igb1", Tag: 20, Pcp: test.pcp, Vlanif: "igb1.20"}
+
+			d := res.TestResourceData()
+			d.SetId(response.Vlanif)
+
+			if err := resourceInterfaceVLAN().updateResource(d, response); err != nil {
+				t.Fatalf("Unable to read the VLAN: %v", err)
+			}
+
+			config := map[string]interface{}{"if": "igb1", "tag": 20}
+
+			for key, value := range test.config {
+				config[key] = value
+			}
+
+			diff, err := res.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), nil)
+
+			if err != nil {
+				t.Fatalf("Unable to plan: %v", err)
+			}
+
+			if diff != nil && !diff.Empty() {
+				t.Errorf("Expected no diff but got %v", diff.Attributes)
+			}
+		})
+	}
+
+	_, request := importRoundTrip(t, resourceInterfaceVLAN(), &pfsenseapi.VLAN{If: "igb1", Tag: 30, Pcp: pfsenseapi.OptionalJSONInt{Value: &five}, Vlanif: "igb1.30"})
+
+	if request.Pcp == nil || *request.Pcp != 5 {
+		t.Errorf("Expected pcp 5 to round trip but got %v", request.Pcp)
+	}
+
+	if err := planResource(resourceInterfaceVLAN(), map[string]interface{}{"if": "igb1", "tag": 30, "pcp": 8}); err == nil {
+		t.Errorf("Expected pcp 8 to be invalid")
+	}
+}
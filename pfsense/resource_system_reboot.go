@@ -0,0 +1,84 @@
+package pfsense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var rebootPollInterval = 10 * time.Second
+
+// waitForReboot polls the version endpoint until the firewall stops answering and then until it answers again.
+func waitForReboot(ctx context.Context, client *providerClient) error {
+	down := false
+
+	for {
+		_, err := client.System.GetVersion(ctx)
+
+		if err != nil {
+			down = true
+		} else if down {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if down {
+				return fmt.Errorf("Firewall did not come back after rebooting: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("Firewall did not go down after requesting a reboot: %w", ctx.Err())
+		case <-time.After(rebootPollInterval):
+		}
+	}
+}
+
+func resourceSystemReboot() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reboots the firewall when created or when `triggers` change, then waits for it to come back. " +
+			"This drops every connection through the firewall, including the one Terraform uses, and a firewall that doesn't come back " +
+			"(e.g. a bad configuration or a failed package) needs console access to recover. The provider must have `allow_reboot = true`.",
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			client := m.(*providerClient)
+
+			if !client.allowReboot {
+				return diag.Errorf("Rebooting is disabled, set allow_reboot = true on the provider to use pfsense_system_reboot")
+			}
+
+			if client.dryRun {
+				return diag.Errorf("Dry run, pfSense not rebooted")
+			}
+
+			if err := client.System.Reboot(ctx); err != nil {
+				return diag.Errorf("Unable to reboot: %v", err)
+			}
+
+			if err := waitForReboot(ctx, client); err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(fmt.Sprint(time.Now().UnixNano()))
+
+			return nil
+		},
+		ReadContext:   schema.NoopContext,
+		DeleteContext: schema.NoopContext,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"triggers": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that cause another reboot when they change, e.g. the settings that need a reboot to apply.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
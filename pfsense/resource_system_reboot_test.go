@@ -0,0 +1,78 @@
+package pfsense
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
+
+func resourceSystemRebootTest() resourceTest {
+	return &schemaResourceTest{
+		name:     "pfsense_system_reboot",
+		resource: resourceSystemReboot(),
+	}
+}
+
+func Test_systemRebootRequiresAllowReboot(t *testing.T) {
+	client, requests := testAPIServer(t, nil)
+
+	r := resourceSystemReboot()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"triggers": map[string]interface{}{"kernel": "1"},
+	})
+
+	if diags := r.CreateContext(context.Background(), d, client); !diags.HasError() {
+		t.Fatalf("Expected an error when allow_reboot is false")
+	}
+
+	if slices.Contains(*requests, "POST /api/v1/system/reboot") {
+		t.Errorf("Reboot was requested when allow_reboot is false")
+	}
+}
+
+func Test_systemRebootWaitsForFirewall(t *testing.T) {
+	defer func(interval time.Duration) { rebootPollInterval = interval }(rebootPollInterval)
+	rebootPollInterval = time.Millisecond
+
+	// The firewall answers once more before going down for two polls
+	versionCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/system/version" {
+			versionCalls++
+
+			if versionCalls == 2 || versionCalls == 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":null}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false)
+	client.allowReboot = true
+
+	r := resourceSystemReboot()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"triggers": map[string]interface{}{"kernel": "1"},
+	})
+
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("Unexpected error %v", diags)
+	}
+
+	if versionCalls != 4 {
+		t.Errorf("Expected to poll until the firewall came back, polled %d times", versionCalls)
+	}
+
+	if d.Id() == "" {
+		t.Errorf("Expected the ID to be set")
+	}
+}
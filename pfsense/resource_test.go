@@ -2,11 +2,17 @@ package pfsense
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	fuzz "github.com/AdaLogics/go-fuzz-headers"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
 )
 
@@ -15,6 +21,24 @@ type resourceTest interface {
 	GetName() string
 }
 
+// schemaResourceTest covers resources that are written directly as a schema.Resource rather than with the generic resource
+type schemaResourceTest struct {
+	name     string
+	resource *schema.Resource
+}
+
+func (r *schemaResourceTest) GetName() string {
+	return r.name
+}
+
+func (r *schemaResourceTest) RunTests(t *testing.T) {
+	t.Run(r.name+"::internalValidate", func(t *testing.T) {
+		if err := r.resource.InternalValidate(nil, true); err != nil {
+			t.Errorf("Resource %s is invalid: %v", r.name, err)
+		}
+	})
+}
+
 type convertFunc[RequestType any, ResponseType any, IdType ~string | ~int] func(*RequestType) (*ResponseType, error)
 type resourceTestFunc[RequestType any, ResponseType any, IdType ~string | ~int] func(t *testing.T)
 
@@ -170,7 +194,7 @@ func (r *tfResourceTest[RequestType, ResponseType, IdType]) functionsAreSet(t *t
 	}
 }
 
-func (r *tfResourceTest[RequestType, ResponseType, IdType]) create(_ context.Context, _ *pfsenseapi.Client, request *RequestType) (*ResponseType, error) {
+func (r *tfResourceTest[RequestType, ResponseType, IdType]) create(_ context.Context, _ *providerClient, request *RequestType) (*ResponseType, error) {
 	result, err := r.convert(request)
 
 	if err != nil {
@@ -196,7 +220,7 @@ func (r *tfResourceTest[RequestType, ResponseType, IdType]) initPartition(partit
 	}
 }
 
-func (r *tfResourceTest[RequestType, ResponseType, IdType]) update(ctx context.Context, client *pfsenseapi.Client, id IdType, request *RequestType) (*ResponseType, error) {
+func (r *tfResourceTest[RequestType, ResponseType, IdType]) update(ctx context.Context, client *providerClient, id IdType, request *RequestType) (*ResponseType, error) {
 	result, err := r.convert(request)
 
 	if err != nil {
@@ -230,7 +254,7 @@ func (r *tfResourceTest[RequestType, ResponseType, IdType]) update(ctx context.C
 	return nil, fmt.Errorf("Test error, unable to find Id %v within partition %s on resource %s", id, partition, r.resource.name)
 }
 
-func (r *tfResourceTest[RequestType, ResponseType, IdType]) delete(ctx context.Context, client *pfsenseapi.Client, partition string, id IdType) error {
+func (r *tfResourceTest[RequestType, ResponseType, IdType]) delete(ctx context.Context, client *providerClient, partition string, id IdType) error {
 	r.initPartition(partition)
 
 	for i, item := range r.currentState[partition] {
@@ -249,7 +273,148 @@ func (r *tfResourceTest[RequestType, ResponseType, IdType]) delete(ctx context.C
 	return fmt.Errorf("Test error, unable to find Id %v within partition %s on resource %s", id, partition, r.resource.name)
 }
 
-func (r *tfResourceTest[RequestType, ResponseType, IdType]) list(_ context.Context, _ *pfsenseapi.Client, partition string) ([]*ResponseType, error) {
+func (r *tfResourceTest[RequestType, ResponseType, IdType]) list(_ context.Context, _ *providerClient, partition string) ([]*ResponseType, error) {
 	r.initPartition(partition)
 	return r.currentState[partition], nil
 }
+
+// importRoundTrip mimics `terraform plan -generate-config-out`, the response is read into state the way an import
+// would, every configurable attribute is written out as config, validated and turned back into a request.
+func importRoundTrip[RequestType any, ResponseType any, IdType ~string | ~int](t *testing.T, r *resource[RequestType, ResponseType, IdType], response *ResponseType) (map[string]interface{}, *RequestType) {
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	r.AddResource(provider)
+	res := provider.ResourcesMap[r.name]
+
+	d := res.TestResourceData()
+
+	if err := r.updateResource(d, response); err != nil {
+		t.Fatalf("Unable to read response into %s: %v", r.name, err)
+	}
+
+	config := map[string]interface{}{}
+
+	for name, property := range res.Schema {
+		if !property.Optional && !property.Required {
+			continue
+		}
+
+		if value, ok := d.GetOk(name); ok {
+			// Raw config has sets as lists, as they're written in HCL
+			if set, ok := value.(*schema.Set); ok {
+				value = set.List()
+			}
+
+			config[name] = value
+		}
+	}
+
+	if diags := res.Validate(terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Fatalf("Generated config for %s is invalid: %v", r.name, diags)
+	}
+
+	request := new(RequestType)
+
+	if err := r.updateRequest(schema.TestResourceDataRaw(t, res.Schema, config), request); err != nil {
+		t.Fatalf("Unable to create request from generated config for %s: %v", r.name, err)
+	}
+
+	return config, request
+}
+
+// planResource runs the same validation and diff customization a plan of a new resource with the config would
+func planResource[RequestType any, ResponseType any, IdType ~string | ~int](r *resource[RequestType, ResponseType, IdType], config map[string]interface{}) error {
+	return planResourceWithMeta(r, config, nil)
+}
+
+// planResourceWithMeta plans a new resource with the provider meta passed to the diff customization
+func planResourceWithMeta[RequestType any, ResponseType any, IdType ~string | ~int](r *resource[RequestType, ResponseType, IdType], config map[string]interface{}, meta interface{}) error {
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	r.AddResource(provider)
+	res := provider.ResourcesMap[r.name]
+	resourceConfig := terraform.NewResourceConfigRaw(config)
+
+	if diags := res.Validate(resourceConfig); diags.HasError() {
+		return fmt.Errorf("%v", diags)
+	}
+
+	// The raw config is only passed along with the state, attributes missing from the config are null
+	rawConfig, err := json.Marshal(config)
+
+	if err != nil {
+		return err
+	}
+
+	state := &terraform.InstanceState{}
+
+	if state.RawConfig, err = ctyjson.Unmarshal(rawConfig, res.CoreConfigSchema().ImpliedType()); err != nil {
+		return err
+	}
+
+	_, err = res.Diff(context.Background(), state, resourceConfig, meta)
+
+	return err
+}
+
+// testAPIServer serves the data for each "METHOD /path" and records the requests made, anything else is null data
+func testAPIServer(t testing.TB, responses map[string]string) (*providerClient, *[]string) {
+	requests := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		requests = append(requests, request)
+
+		data, ok := responses[request]
+
+		if !ok {
+			data = "null"
+		}
+
+		_, _ = w.Write([]byte(`{"status":"ok","code":200,"return":0,"message":"Success","data":` + data + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return newProviderClient(pfsenseapi.NewClient(pfsenseapi.Config{Host: server.URL}), false), &requests
+}
+
+func Test_dryRun(t *testing.T) {
+	client, requests := testAPIServer(t, map[string]string{})
+	client.dryRun = true
+
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	resourceFirewallAlias().AddResource(provider)
+	res := provider.ResourcesMap["pfsense_firewall_alias"]
+	config := map[string]interface{}{
+		"name":   "web_servers",
+		"type":   "host",
+		"target": []interface{}{map[string]interface{}{"address": "10.0.0.10"}},
+	}
+
+	d := schema.TestResourceDataRaw(t, res.Schema, config)
+	diags := res.CreateContext(context.Background(), d, client)
+
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, `"10.0.0.10"`) {
+		t.Errorf("Expected create to fail with the request but got %v", diags)
+	}
+
+	if d.Id() != "" {
+		t.Errorf("Expected no ID to be set but got %s", d.Id())
+	}
+
+	d.SetId("web_servers")
+
+	if diags := res.UpdateContext(context.Background(), d, client); !diags.HasError() || !strings.Contains(diags[0].Summary, "web_servers not updated") {
+		t.Errorf("Expected update to fail but got %v", diags)
+	}
+
+	if diags := res.DeleteContext(context.Background(), d, client); !diags.HasError() || d.Id() != "web_servers" {
+		t.Errorf("Expected delete to fail and keep the ID but got %v", diags)
+	}
+
+	if diags := resourceCommit().CreateContext(context.Background(), schema.TestResourceDataRaw(t, resourceCommit().Schema, map[string]interface{}{}), client); !diags.HasError() {
+		t.Errorf("Expected commit to fail")
+	}
+
+	if len(*requests) != 0 {
+		t.Errorf("Expected nothing to be sent but got %v", *requests)
+	}
+}
@@ -3,6 +3,8 @@ package pfsense
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,21 +17,52 @@ func splitDns(dns string) (string, string) {
 	return parts[0], strings.Join(parts[1:], ".")
 }
 
+// hashIPAddress hashes the canonical form of the address, so e.g. 2001:DB8::1 and 2001:db8:0::1 are the same set item
+func hashIPAddress(v interface{}) int {
+	if addr, err := netip.ParseAddr(v.(string)); err == nil {
+		return schema.HashString(addr.String())
+	}
+
+	return schema.HashString(v)
+}
+
+// sortedIPAddresses sorts the addresses with IPv4 before IPv6 and drops any that are the same once parsed, pfSense
+// keeps them in the order they were written
+func sortedIPAddresses(addresses []string) []string {
+	sorted := slices.Clone(addresses)
+
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		aAddr, aErr := netip.ParseAddr(a)
+		bAddr, bErr := netip.ParseAddr(b)
+
+		if aErr != nil || bErr != nil {
+			return strings.Compare(a, b)
+		}
+
+		return aAddr.Compare(bAddr)
+	})
+
+	return slices.CompactFunc(sorted, func(a, b string) bool {
+		return hashIPAddress(a) == hashIPAddress(b)
+	})
+}
+
 func resourceUnboundHostOverride() *resource[pfsenseapi.UnboundHostOverride, pfsenseapi.UnboundHostOverride, string] {
 	return &resource[pfsenseapi.UnboundHostOverride, pfsenseapi.UnboundHostOverride, string]{
 		name:        "pfsense_unbound_host_override",
 		description: "Unbound Host Override",
-		delete: func(ctx context.Context, client *pfsenseapi.Client, _ string, dns string) error {
+		delete: func(ctx context.Context, client *providerClient, _ string, dns string) error {
 			host_name, domain_name := splitDns(dns)
 			return client.Unbound.DeleteHostOverride(ctx, host_name, domain_name, true)
 		},
-		list: func(ctx context.Context, client *pfsenseapi.Client, _ string) ([]*pfsenseapi.UnboundHostOverride, error) {
+		list: func(ctx context.Context, client *providerClient, _ string) ([]*pfsenseapi.UnboundHostOverride, error) {
 			return client.Unbound.ListHostOverrides(ctx)
 		},
-		update: func(ctx context.Context, client *pfsenseapi.Client, _ string, request *pfsenseapi.UnboundHostOverride) (*pfsenseapi.UnboundHostOverride, error) {
+		// There is no unbound apply endpoint to defer to, so host overrides are always applied regardless of auto_reload
+		update: func(ctx context.Context, client *providerClient, _ string, request *pfsenseapi.UnboundHostOverride) (*pfsenseapi.UnboundHostOverride, error) {
 			return client.Unbound.UpdateHostOverride(ctx, request, true)
 		},
-		create: func(ctx context.Context, client *pfsenseapi.Client, request *pfsenseapi.UnboundHostOverride) (*pfsenseapi.UnboundHostOverride, error) {
+		create: func(ctx context.Context, client *providerClient, request *pfsenseapi.UnboundHostOverride) (*pfsenseapi.UnboundHostOverride, error) {
 			return client.Unbound.CreateHostOverride(ctx, request, true)
 		},
 		properties: map[string]*resourceProperty[pfsenseapi.UnboundHostOverride, pfsenseapi.UnboundHostOverride]{
@@ -51,27 +84,29 @@ func resourceUnboundHostOverride() *resource[pfsenseapi.UnboundHostOverride, pfs
 			},
 			"ip_addresses": {
 				schema: &schema.Schema{
-					Type:        schema.TypeList,
+					Type:        schema.TypeSet,
 					Required:    true,
 					MinItems:    1,
-					Description: "IPv4 or IPv6 of the host override.",
+					Description: "IPv4 and IPv6 addresses of the host override, e.g. one of each for A and AAAA records. The order doesn't matter and the same address written differently is only included once.",
 					Elem: &schema.Schema{
 						Type:         schema.TypeString,
 						ValidateFunc: validation.IsIPAddress,
 					},
+					Set: hashIPAddress,
 				},
 				updateRequest: func(d *schema.ResourceData, name string, req *pfsenseapi.UnboundHostOverride) error {
-					var err error
-					req.IP, err = interfaceToStringArray(d.Get(name))
+					addresses, err := interfaceToStringArray(d.Get(name).(*schema.Set).List())
 
 					if err != nil {
 						return err
 					}
 
+					req.IP = sortedIPAddresses(addresses)
+
 					return nil
 				},
 				getFromResponse: func(req *pfsenseapi.UnboundHostOverride) (interface{}, error) {
-					return req.IP, nil
+					return sortedIPAddresses(req.IP), nil
 				},
 			},
 			"description": {
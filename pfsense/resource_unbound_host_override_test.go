@@ -1,9 +1,72 @@
 package pfsense
 
-import "github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/sjafferali/pfsense-api-goclient/pfsenseapi"
+)
 
 func resourceUnboundHostOverrideTest() resourceTest {
 	return &tfResourceTest[pfsenseapi.UnboundHostOverride, pfsenseapi.UnboundHostOverride, string]{
 		resource: resourceUnboundHostOverride(),
 	}
 }
+
+func Test_unboundHostOverrideImportRoundTrip(t *testing.T) {
+	override := &pfsenseapi.UnboundHostOverride{
+		Host:   "nas",
+		Domain: "example.com",
+		IP:     pfsenseapi.StringArray{"2001:db8::10", "192.168.1.10"},
+	}
+
+	_, request := importRoundTrip(t, resourceUnboundHostOverride(), override)
+
+	if expected := (pfsenseapi.StringArray{"192.168.1.10", "2001:db8::10"}); !reflect.DeepEqual(request.IP, expected) {
+		t.Errorf("Expected addresses %v but got %v", expected, request.IP)
+	}
+}
+
+func Test_unboundHostOverrideAddressesIgnoreOrder(t *testing.T) {
+	r := resourceUnboundHostOverride()
+	provider := &schema.Provider{ResourcesMap: map[string]*schema.Resource{}}
+	r.AddResource(provider)
+	res := provider.ResourcesMap[r.name]
+
+	tests := map[string]struct {
+		stored  pfsenseapi.StringArray
+		config  []interface{}
+		changed bool
+	}{
+		"same order":     {pfsenseapi.StringArray{"192.168.1.10", "2001:db8::10"}, []interface{}{"192.168.1.10", "2001:db8::10"}, false},
+		"reordered":      {pfsenseapi.StringArray{"2001:db8::10", "192.168.1.10"}, []interface{}{"192.168.1.10", "2001:db8::10"}, false},
+		"written longer": {pfsenseapi.StringArray{"192.168.1.10", "2001:db8::10"}, []interface{}{"2001:DB8:0::10", "192.168.1.10"}, false},
+		"duplicated":     {pfsenseapi.StringArray{"192.168.1.10", "192.168.1.10"}, []interface{}{"192.168.1.10"}, false},
+		"added":          {pfsenseapi.StringArray{"192.168.1.10"}, []interface{}{"192.168.1.10", "2001:db8::10"}, true},
+		"replaced":       {pfsenseapi.StringArray{"192.168.1.10"}, []interface{}{"192.168.1.11"}, true},
+	}
+
+	for name, test := range tests {
+		d := res.TestResourceData()
+
+		if err := r.updateResource(d, &pfsenseapi.UnboundHostOverride{Host: "nas", Domain: "example.com", IP: test.stored}); err != nil {
+			t.Fatalf("%s: Unable to read response: %v", name, err)
+		}
+
+		d.SetId("nas.example.com")
+
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{"dns": "nas.example.com", "ip_addresses": test.config})
+		diff, err := res.Diff(context.Background(), d.State(), config, nil)
+
+		if err != nil {
+			t.Fatalf("%s: Unable to plan: %v", name, err)
+		}
+
+		if changed := diff != nil && !diff.Empty(); changed != test.changed {
+			t.Errorf("%s: Expected changed to be %t but got diff %v", name, test.changed, diff)
+		}
+	}
+}